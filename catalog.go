@@ -0,0 +1,233 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CatalogFormat is the format of an exported catalog of codes.
+type CatalogFormat string
+
+const (
+	// CatalogJSON is a JSON array of CatalogEntry.
+	CatalogJSON CatalogFormat = "json"
+	// CatalogYAML is an indented JSON array of CatalogEntry, which is valid YAML
+	// as well, so it is read by the YAML tools without a YAML encoder here.
+	CatalogYAML CatalogFormat = "yaml"
+	// CatalogCSV is a CSV table with a header row.
+	CatalogCSV CatalogFormat = "csv"
+	// CatalogMarkdown is a markdown table like the one of codegen -doc.
+	CatalogMarkdown CatalogFormat = "markdown"
+)
+
+// catalogContentTypes are the media types of the catalog formats, in the order
+// of preference of CatalogHandler.
+var catalogContentTypes = []struct {
+	format      CatalogFormat
+	contentType string
+}{
+	{CatalogJSON, contentTypeJSON},
+	{CatalogYAML, "application/yaml"},
+	{CatalogCSV, "text/csv"},
+	{CatalogMarkdown, "text/markdown"},
+}
+
+// CatalogEntry describes a registered code.
+type CatalogEntry struct {
+	// Namespace is the namespace of the registry of the code, empty for DefaultRegistry.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Code is the business code.
+	Code int `json:"code"`
+
+	// HTTP is the HTTP status of the code.
+	HTTP int `json:"http"`
+
+	// GRPC is the gRPC code of the code, e.g. "NotFound".
+	GRPC string `json:"grpc"`
+
+	// Message is the external (user) facing error text.
+	Message string `json:"message"`
+
+	// Reference is the detail document of the code, if any.
+	Reference string `json:"reference,omitempty"`
+
+	// Range is the owner of the claimed range of the code, if any.
+	Range string `json:"range,omitempty"`
+
+	// Metadata is the metadata of the definition of the code, see LoadCoders.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Codes returns the Coders registered in DefaultRegistry, ordered by code.
+func Codes() []Coder {
+	return DefaultRegistry.Codes()
+}
+
+// Codes returns the Coders registered in r, ordered by code.
+func (r *Registry) Codes() []Coder {
+	return r.load().sortedCodes()
+}
+
+func (s *registryState) sortedCodes() []Coder {
+	coders := make([]Coder, 0, len(s.codes))
+	for _, coder := range s.codes {
+		coders = append(coders, coder)
+	}
+	sort.Slice(coders, func(i, j int) bool { return coders[i].Code() < coders[j].Code() })
+
+	return coders
+}
+
+// Catalog returns the codes of DefaultRegistry followed by the ones of the registries
+// with a namespace, ordered by namespace, so it includes the codes the libraries
+// registered in registries of their own.
+func Catalog() []CatalogEntry {
+	registriesMu.Lock()
+	named := make([]*Registry, 0, len(registries))
+	for _, r := range registries {
+		named = append(named, r)
+	}
+	registriesMu.Unlock()
+
+	sort.Slice(named, func(i, j int) bool { return named[i].namespace < named[j].namespace })
+
+	entries := DefaultRegistry.Catalog()
+	for _, r := range named {
+		entries = append(entries, r.Catalog()...)
+	}
+
+	return entries
+}
+
+// Catalog returns the codes of r, ordered by code.
+func (r *Registry) Catalog() []CatalogEntry {
+	state := r.load()
+
+	entries := make([]CatalogEntry, 0, len(state.codes))
+	for _, coder := range state.sortedCodes() {
+		entry := CatalogEntry{
+			Namespace: r.namespace,
+			Code:      coder.Code(),
+			HTTP:      coder.HTTPStatus(),
+			GRPC:      grpcCode(coder).String(),
+			Message:   coder.String(),
+			Reference: coder.Reference(),
+		}
+		if cr := state.rangeOf(coder.Code()); cr != nil {
+			entry.Range = cr.name
+		}
+		if lc, ok := coder.(*loadedCoder); ok {
+			entry.Metadata = lc.metadata
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// WriteCatalog writes entries to w in format.
+func WriteCatalog(w io.Writer, entries []CatalogEntry, format CatalogFormat) error {
+	if entries == nil {
+		entries = []CatalogEntry{}
+	}
+
+	switch format {
+	case CatalogJSON, CatalogYAML:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		if format == CatalogYAML {
+			enc.SetIndent("", "  ")
+		}
+
+		return enc.Encode(entries)
+	case CatalogCSV:
+		return writeCatalogCSV(w, entries)
+	case CatalogMarkdown:
+		return writeCatalogMarkdown(w, entries)
+	default:
+		return fmt.Errorf("catalog: unknown format %q", format)
+	}
+}
+
+func writeCatalogCSV(w io.Writer, entries []CatalogEntry) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"namespace", "code", "http", "grpc", "message", "reference", "range"})
+	for _, e := range entries {
+		_ = cw.Write([]string{
+			e.Namespace, strconv.Itoa(e.Code), strconv.Itoa(e.HTTP), e.GRPC, e.Message, e.Reference, e.Range,
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func writeCatalogMarkdown(w io.Writer, entries []CatalogEntry) error {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ").Replace
+
+	var b bytes.Buffer
+	b.WriteString("| Namespace | Code | HTTP Code | gRPC Code | Description | Reference | Range |\n")
+	b.WriteString("| --------- | ---- | --------- | --------- | ----------- | --------- | ----- |\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | %d | %d | %s | %s | %s | %s |\n",
+			cell(e.Namespace), e.Code, e.HTTP, e.GRPC, cell(e.Message), cell(e.Reference), cell(e.Range))
+	}
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// CatalogHandler returns a http.Handler serving the live catalog of registries, of
+// Catalog if none is given. The format is the one of the "format" query parameter,
+// e.g. "?format=csv", or else is negotiated with the Accept header, JSON by default.
+func CatalogHandler(registries ...*Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entries []CatalogEntry
+		for _, reg := range registries {
+			entries = append(entries, reg.Catalog()...)
+		}
+		if len(registries) == 0 {
+			entries = Catalog()
+		}
+
+		format := CatalogFormat(r.URL.Query().Get("format"))
+		if format == "" {
+			offers := make([]string, 0, len(catalogContentTypes))
+			for _, ct := range catalogContentTypes {
+				offers = append(offers, ct.contentType)
+			}
+
+			accepted := negotiate(r.Header.Get("Accept"), offers...)
+			for _, ct := range catalogContentTypes {
+				if ct.contentType == accepted {
+					format = ct.format
+				}
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := WriteCatalog(&buf, entries, format); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		header := w.Header()
+		for _, ct := range catalogContentTypes {
+			if ct.format == format {
+				header.Set("Content-Type", ct.contentType+"; charset=utf-8")
+			}
+		}
+		header.Set("X-Content-Type-Options", "nosniff")
+		_, _ = w.Write(buf.Bytes())
+	})
+}
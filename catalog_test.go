@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func catalogRegistry() *Registry {
+	r := NewRegistry("")
+	r.RegisterRange("account", 110000, 110099).Register(defaultCoder{110001, 404, "Account | user not found", ""})
+	r.Register(defaultCoder{100002, 400, "Invalid <name>", "http://example.com/100002"})
+
+	return r
+}
+
+func TestCodes(t *testing.T) {
+	r := catalogRegistry()
+
+	coders := r.Codes()
+	assert.Len(t, coders, 2)
+	assert.Equal(t, 100002, coders[0].Code())
+	assert.Equal(t, 110001, coders[1].Code())
+
+	assert.Contains(t, Codes(), UnknownCoder)
+}
+
+func TestCatalog(t *testing.T) {
+	named := newTestRegistry(t, "test.catalog")
+	named.Register(defaultCoder{100001, 409, "Conflict", ""})
+
+	var found bool
+	for _, e := range Catalog() {
+		if e.Namespace == "test.catalog" {
+			found = true
+			assert.Equal(t, CatalogEntry{Namespace: "test.catalog", Code: 100001, HTTP: 409, GRPC: "Aborted", Message: "Conflict"}, e)
+		}
+	}
+	assert.True(t, found)
+	assert.Equal(t, 1, Catalog()[0].Code)
+}
+
+func TestWriteCatalog(t *testing.T) {
+	entries := catalogRegistry().Catalog()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCatalog(&buf, entries, CatalogJSON))
+	assert.Equal(t, `[{"code":100002,"http":400,"grpc":"InvalidArgument","message":"Invalid <name>","reference":"http://example.com/100002"},`+
+		`{"code":110001,"http":404,"grpc":"NotFound","message":"Account | user not found","range":"account"}]`+"\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteCatalog(&buf, entries, CatalogYAML))
+	var decoded []CatalogEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, entries, decoded)
+	assert.Contains(t, buf.String(), "\n  {\n    \"code\": 100002,\n")
+
+	buf.Reset()
+	assert.NoError(t, WriteCatalog(&buf, entries, CatalogCSV))
+	assert.Equal(t, "namespace,code,http,grpc,message,reference,range\n"+
+		",100002,400,InvalidArgument,Invalid <name>,http://example.com/100002,\n"+
+		",110001,404,NotFound,Account | user not found,,account\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteCatalog(&buf, entries, CatalogMarkdown))
+	assert.Equal(t, "| Namespace | Code | HTTP Code | gRPC Code | Description | Reference | Range |\n"+
+		"| --------- | ---- | --------- | --------- | ----------- | --------- | ----- |\n"+
+		"|  | 100002 | 400 | InvalidArgument | Invalid <name> | http://example.com/100002 |  |\n"+
+		`|  | 110001 | 404 | NotFound | Account \| user not found |  | account |`+"\n", buf.String())
+
+	assert.Error(t, WriteCatalog(&buf, entries, "xml"))
+
+	buf.Reset()
+	assert.NoError(t, WriteCatalog(&buf, nil, CatalogJSON))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestCatalogHandler(t *testing.T) {
+	r := catalogRegistry()
+	h := CatalogHandler(r)
+
+	tests := []struct {
+		target, accept string
+		contentType    string
+		status         int
+	}{
+		{"/", "", "application/json; charset=utf-8", http.StatusOK},
+		{"/", "text/csv", "text/csv; charset=utf-8", http.StatusOK},
+		{"/", "text/markdown, application/json;q=0.5", "text/markdown; charset=utf-8", http.StatusOK},
+		{"/?format=yaml", "text/csv", "application/yaml; charset=utf-8", http.StatusOK},
+		{"/?format=xml", "", "text/plain; charset=utf-8", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, tt.status, rec.Code, tt.target+" "+tt.accept)
+		assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"), tt.target+" "+tt.accept)
+	}
+
+	// the catalog is live
+	r.Register(defaultCoder{100003, 500, "Later", ""})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var entries []CatalogEntry
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	assert.Len(t, entries, 3)
+}
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"sync"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	gcodes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
 )
 
 // global default error codes
@@ -31,6 +33,49 @@ type Coder interface {
 	Code() int
 }
 
+// GRPCCoder is implemented by Coders which set their gRPC code explicitly, instead
+// of deriving it from their HTTP status with ToGRPCCode, e.g. FailedPrecondition or
+// OutOfRange rather than InvalidArgument for HTTP 400, AlreadyExists rather than
+// Aborted for HTTP 409.
+type GRPCCoder interface {
+	// GRPCCode returns the gRPC code of the status of the errors of the code.
+	GRPCCode() gcodes.Code
+}
+
+// WithGRPCCode returns coder with the explicit gRPC code code, see GRPCCoder.
+func WithGRPCCode(coder Coder, code gcodes.Code) Coder {
+	return &grpcCoder{Coder: coder, grpc: code}
+}
+
+// grpcCode returns the gRPC code of coder, the explicit one if it is a GRPCCoder.
+func grpcCode(coder Coder) gcodes.Code {
+	if c, ok := coder.(GRPCCoder); ok {
+		return c.GRPCCode()
+	}
+
+	return ToGRPCCode(coder.HTTPStatus())
+}
+
+// grpcCoder is a Coder with an explicit gRPC code.
+type grpcCoder struct {
+	Coder
+	grpc gcodes.Code
+}
+
+// GRPCCode returns the explicit gRPC code.
+func (coder *grpcCoder) GRPCCode() gcodes.Code {
+	return coder.grpc
+}
+
+// Localize returns the translation of the wrapped Coder, if it is a Localizer.
+func (coder *grpcCoder) Localize(lang string) (string, bool) {
+	if l, ok := coder.Coder.(Localizer); ok {
+		return l.Localize(lang)
+	}
+
+	return "", false
+}
+
 type defaultCoder struct {
 	// C refers to the integer code of the ErrCode.
 	C int
@@ -71,71 +116,46 @@ func (coder defaultCoder) Reference() string {
 	return coder.Ref
 }
 
-// codes contains a map of error codes to metadata.
-var (
-	codes   = map[int]Coder{}
-	codeMux = &sync.Mutex{}
-)
-
-// Register register a user define error code.
+// Register register a user define error code in DefaultRegistry.
 // It will overrid the exist code.
 func Register(coder Coder) {
-	if coder.Code() == 0 {
-		panic("code `0` is reserved by `github.com/panda/errors` as unknownCode error code")
-	}
-
-	codeMux.Lock()
-	defer codeMux.Unlock()
-
-	codes[coder.Code()] = coder
+	DefaultRegistry.Register(coder)
 }
 
-// MustRegister register a user define error code.
+// MustRegister register a user define error code in DefaultRegistry.
 // It will panic when the same Code already exist.
 func MustRegister(coder Coder) {
-	if coder.Code() == 0 {
-		panic("code '0' is reserved by 'github.com/panda/errors' as ErrUnknown error code")
-	}
-
-	codeMux.Lock()
-	defer codeMux.Unlock()
-
-	if _, ok := codes[coder.Code()]; ok {
-		panic(fmt.Sprintf("code: %d already exist", coder.Code()))
-	}
-
-	codes[coder.Code()] = coder
+	DefaultRegistry.MustRegister(coder)
 }
 
 // ParseCoder parse any error into *WithCode.
 // nil error will return nil direct.
 // None withStack error will be parsed as ErrUnknown.
+// A gRPC status error carrying a *Status detail, even wrapped e.g. by WithMessage,
+// is parsed into a Coder built from the detail, so the code does not need to be
+// registered.
 func ParseCoder(err error) Coder {
-	if err == nil {
-		return nil
-	}
-
-	v := new(withCode)
+	return DefaultRegistry.ParseCoder(err)
+}
 
-	if errors.As(err, &v) {
-		if coder, ok := codes[v.code]; ok {
-			return coder
+// lookupCoder returns the registered Coder of the first withCode in err's chain,
+// looked up in r unless the withCode has a registry of its own.
+// For an aggregate error the Coder is selected among its coded children.
+func lookupCoder(err error, r *Registry) (Coder, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *withCode:
+			return e.lookup(r)
+		case *joinError:
+			return selectCoder(e.errs, e.selector, r)
+		case interface{ Unwrap() []error }:
+			return selectCoder(e.Unwrap(), nil, r)
 		}
-	}
 
-	ge, ok := status.FromError(err)
-	if !ok {
-		return UnknownCoder
+		err = errors.Unwrap(err)
 	}
 
-	for _, detail := range ge.Details() {
-		switch d := detail.(type) {
-		case *Status:
-			return codes[int(d.Code)]
-		}
-	}
-
-	return UnknownCoder
+	return nil, false
 }
 
 // GRPCErr convert error to grpc error.
@@ -150,40 +170,37 @@ func ParseCoder(err error) Coder {
 
 // GRPCStatus convert error to grpc *status.Status.
 // if err no register Coder, return unknown grpc error.
+// The gRPC code is the one of the Coder if it is a GRPCCoder, or else is derived
+// from its HTTP status.
+// A status error received from another service, e.g. returned by a client call,
+// is forwarded with its gRPC code and the code of its *Status detail, even wrapped.
+// The fields of err's chain are carried by the metadata of the *Status detail.
+// The message is the Public one, in debug mode the internal message and the stack
+// are carried by an errdetails.DebugInfo detail.
 func GRPCStatus(err error) *status.Status {
-	if err == nil {
-		return nil
-	}
-
-	var c Coder = UnknownCoder
-	if v := new(withCode); errors.As(err, &v) {
-		coder, ok := codes[v.code]
-		if ok {
-			c = coder
-		}
-	}
-
-	s, _ := status.New(ToGRPCCode(c.HTTPStatus()), c.String()).
-		WithDetails(&Status{
-			Code: int32(c.Code()),
-			Http: int32(c.HTTPStatus()),
-			Ref:  c.Reference(),
-		})
-
-	return s
+	return DefaultRegistry.GRPCStatus(err)
 }
 
 // GRPCCodeStatus convert code to grpc *status.Status.
 // if err no register Coder, return unknown grpc error.
 // If code is known, it is more efficient to use this method than GRPCStatus.
 func GRPCCodeStatus(code int) *status.Status {
-	c := GetCoder(code)
-	s, _ := status.New(ToGRPCCode(c.HTTPStatus()), c.String()).
-		WithDetails(&Status{
-			Code: int32(c.Code()),
-			Http: int32(c.HTTPStatus()),
-			Ref:  c.Reference(),
-		})
+	return DefaultRegistry.GRPCCodeStatus(code)
+}
+
+// newStatus returns the status of c, carrying the fields of err if not nil.
+func newStatus(c Coder, err error) *status.Status {
+	details := []protoiface.MessageV1{&Status{
+		Code:     int32(c.Code()),
+		Http:     int32(c.HTTPStatus()),
+		Ref:      c.Reference(),
+		Metadata: metadata(err),
+	}}
+	if debug && err != nil {
+		details = append(details, debugInfo(err))
+	}
+
+	s, _ := status.New(grpcCode(c), publicMessage(c)).WithDetails(details...)
 
 	return s
 }
@@ -192,22 +209,21 @@ func GRPCCodeStatus(code int) *status.Status {
 // not found return ErrUnknown
 // note: can not be change
 func GetCoder(code int) Coder {
-	if coder, ok := codes[code]; ok {
-		return coder
-	}
-
-	return UnknownCoder
+	return DefaultRegistry.GetCoder(code)
 }
 
 // IsCode reports whether any error in err's chain contains the given error code.
+// An aggregate error matches when any of its errors matches.
 func IsCode(err error, code int) bool {
-	if coder := ParseCoder(err); coder != nil {
-		return coder.Code() == code
-	}
-
-	return false
+	return DefaultRegistry.IsCode(err, code)
 }
 
-func init() {
-	codes[UnknownCoder.Code()] = UnknownCoder
+// debugInfo returns the internal message and the stack of err.
+func debugInfo(err error) *errdetails.DebugInfo {
+	info := &errdetails.DebugInfo{Detail: Internal(err)}
+	for _, f := range MergedStackTrace(err) {
+		info.StackEntries = append(info.StackEntries, fmt.Sprintf("%s %s:%d", f.name(), f.file(), f.line()))
+	}
+
+	return info
 }
@@ -4,6 +4,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/ast"
@@ -17,9 +18,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/go-leo/errors"
 	"golang.org/x/tools/go/packages"
+	"google.golang.org/grpc/codes"
 )
 
 var (
@@ -29,6 +33,8 @@ var (
 	buildTags   = flag.String("tags", "", "comma-separated list of build tags to apply")
 	registerpkg = flag.String("registerpkg", "", "register function's pkg")
 	doc         = flag.Bool("doc", false, "if true only generate error code documentation in markdown format")
+	i18n        = flag.String("i18n", "", "comma-separated list of translation catalogs `<locale>.json`, keyed by constant name")
+	codeRange   = flag.String("range", "", "the `CodeRange` registering the codes, e.g. accountCodes, required by errors.SetStrictRanges")
 )
 
 // Usage is a replacement usage function for the flags package.
@@ -67,6 +73,10 @@ func main() {
 	g := Generator{
 		trimPrefix:  *trimprefix,
 		registerPkg: *registerpkg,
+		codeRange:   *codeRange,
+	}
+	if len(*i18n) > 0 {
+		g.catalogs = loadCatalogs(strings.Split(*i18n, ","))
 	}
 	// TODO(suzmue): accept other patterns for packages (directories, list of files, import paths, etc).
 	if len(args) == 1 && isDirectory(args[0]) {
@@ -98,7 +108,9 @@ func main() {
 			if g.registerPkg != "" {
 				g.Printf("import %s", "code \""+g.registerPkg+"\"\n")
 			}
-			g.Printf("import %s", "\"fmt\"\n")
+			if g.hasGRPCCodes(typeName) {
+				g.Printf("import %s", "\"google.golang.org/grpc/codes\"\n")
+			}
 			g.generate(typeName)
 			g.generateErrFuncs(typeName)
 			// Format the output.
@@ -144,6 +156,34 @@ type Generator struct {
 
 	trimPrefix  string
 	registerPkg string
+	// codeRange is the CodeRange registering the codes, if any.
+	codeRange string
+	catalogs  []*Catalog
+}
+
+// Catalog holds the translated error code descriptions of a locale.
+type Catalog struct {
+	locale   string
+	messages map[string]string // keyed by constant name
+}
+
+// loadCatalogs reads the translation catalogs, the locale of a catalog is its file name.
+// loadCatalogs exits if there is an error.
+func loadCatalogs(paths []string) []*Catalog {
+	catalogs := make([]*Catalog, 0, len(paths))
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatalf("reading catalog: %s", err)
+		}
+		c := &Catalog{locale: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+		if err := json.Unmarshal(data, &c.messages); err != nil {
+			log.Fatalf("parsing catalog %s: %s", path, err)
+		}
+		catalogs = append(catalogs, c)
+	}
+
+	return catalogs
 }
 
 // Printf like fmt.Printf, but add the string to g.buf.
@@ -227,17 +267,56 @@ func (g *Generator) generate(typeName string) {
 	// Generate code that will fail if the constants change value.
 	g.Printf("\t// init register error codes defines in this source code to `github.com/go-leo/errors`\n")
 	g.Printf("func init() {\n")
+	// the codes of a range are registered through it, by the In variants of the helpers
+	suffix, in := "", ""
+	if g.codeRange != "" {
+		suffix, in = "In", g.codeRange+", "
+	}
 	for _, v := range values {
-		code, description := v.ParseComment()
-		if g.registerPkg != "" {
-			g.Printf("\tcode.Register(%s, %s, \"%s\")\n", v.originalName, code, description)
-		} else {
-			g.Printf("\tregister(%s, %s, \"%s\")\n", v.originalName, code, description)
+		code, grpcCode, description := v.ParseComment()
+		switch {
+		case grpcCode != "" && g.registerPkg != "":
+			g.Printf("\tcode.RegisterGRPC%s(%s%s, %s, codes.%s, \"%s\")\n", suffix, in, v.originalName, code, grpcCode, description)
+		case grpcCode != "":
+			g.Printf("\tregisterGRPC%s(%s%s, %s, codes.%s, \"%s\")\n", suffix, in, v.originalName, code, grpcCode, description)
+		case g.registerPkg != "":
+			g.Printf("\tcode.Register%s(%s%s, %s, \"%s\")\n", suffix, in, v.originalName, code, description)
+		default:
+			g.Printf("\tregister%s(%s%s, %s, \"%s\")\n", suffix, in, v.originalName, code, description)
+		}
+	}
+	for _, c := range g.catalogs {
+		g.Printf("\terrors.RegisterTranslations(%q, map[int]string{\n", c.locale)
+		for _, v := range values {
+			message, ok := c.messages[v.originalName]
+			if !ok {
+				log.Fatalf("locale %s is missing a translation of %s", c.locale, v.originalName)
+			}
+			g.Printf("\t\t%s: %q,\n", v.originalName, message)
 		}
+		g.Printf("\t})\n")
 	}
 	g.Printf("}\n")
 }
 
+// hasGRPCCodes reports whether a constant of the named type has a gRPC code.
+func (g *Generator) hasGRPCCodes(typeName string) bool {
+	for _, file := range g.pkg.files {
+		file.typeName = typeName
+		file.values = nil
+		if file.file != nil {
+			ast.Inspect(file.file, file.genDecl)
+			for _, v := range file.values {
+				if _, grpcCode, _ := v.ParseComment(); grpcCode != "" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 // generateDocs produces error code markdown document for the named type.
 func (g *Generator) generateDocs(typeName string) {
 	values := make([]Value, 0, 100)
@@ -262,9 +341,13 @@ func (g *Generator) generateDocs(typeName string) {
 	// Generate code that will fail if the constants change value.
 	g.Printf(buf.String())
 	for _, v := range values {
-		code, description := v.ParseComment()
+		code, grpcCode, description := v.ParseComment()
+		if grpcCode == "" {
+			httpCode, _ := strconv.Atoi(code)
+			grpcCode = errors.ToGRPCCode(httpCode).String()
+		}
 		// g.Printf("\tregister(%s, %s, \"%s\")\n", v.originalName, code, description)
-		g.Printf("| %s | %d | %s | %s |\n", v.originalName, v.value, code, description)
+		g.Printf("| %s | %d | %s | %s | %s |\n", v.originalName, v.value, code, grpcCode, description)
 	}
 	g.Printf("\n")
 }
@@ -288,7 +371,7 @@ func (g *Generator) generateErrFuncs(typeName string) {
 
 	var ew errorWrapper
 	for _, v := range values {
-		code, description := v.ParseComment()
+		code, _, description := v.ParseComment()
 		err := &errorInfo{
 			Name:     v.originalName,
 			HTTPCode: code,
@@ -344,21 +427,38 @@ func (v *Value) String() string {
 	return v.str
 }
 
-// ParseComment parse comment to http code and error code description.
-func (v *Value) ParseComment() (string, string) {
-	reg := regexp.MustCompile(`\w\s*-\s*(\d{3})\s*:\s*([\w\W]*)\s*\.\n*`)
+// grpcCodes are the names of the gRPC codes, e.g. "FailedPrecondition".
+var grpcCodes = func() map[string]bool {
+	names := make(map[string]bool, codes.Unauthenticated+1)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		names[c.String()] = true
+	}
+
+	return names
+}()
+
+// ParseComment parse comment to http code, gRPC code and error code description.
+// The gRPC code is optional, e.g. "ErrX - 400(FailedPrecondition): description.",
+// it is "" if the comment has none.
+// ParseComment exits if the gRPC code is unknown.
+func (v *Value) ParseComment() (string, string, string) {
+	reg := regexp.MustCompile(`\w\s*-\s*(\d{3})\s*(?:\(\s*(\w+)\s*\))?\s*:\s*([\w\W]*)\s*\.\n*`)
 	if !reg.MatchString(v.comment) {
 		log.Printf("constant '%s' have wrong comment format, register with 500 as default", v.originalName)
 
-		return "500", "Internal server error"
+		return "500", "", "Internal server error"
 	}
 
 	groups := reg.FindStringSubmatch(v.comment)
-	if len(groups) != 3 {
-		return "500", "Internal server error"
+	if len(groups) != 4 {
+		return "500", "", "Internal server error"
+	}
+
+	if groups[2] != "" && !grpcCodes[groups[2]] {
+		log.Fatalf("constant '%s' have unknown gRPC code %s", v.originalName, groups[2])
 	}
 
-	return groups[1], groups[2]
+	return groups[1], groups[2], groups[3]
 }
 
 // nolint: gocognit
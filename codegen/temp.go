@@ -25,7 +25,7 @@ func Is{{.Name}}(err error) bool {
 
 // {{ .Comment }}
 func New{{ .Name }}(format string, args ...interface{}) error {
-	 return errors.NewWithCodeX({{ .Name }}, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	 return errors.NewWithCodeX({{ .Name }}, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
 
 {{- end }}
@@ -46,12 +46,12 @@ var errCodeDocPrefix = `# 错误码
 }
 {{.}}{{.}}{{.}}
 
-上述返回中 {{.}}code{{.}} 表示错误码，{{.}}message{{.}} 表示该错误的具体信息。每个错误同时也对应一个 HTTP 状态码，比如上述错误码对应了 HTTP 状态码 500(Internal Server Error)。
+上述返回中 {{.}}code{{.}} 表示错误码，{{.}}message{{.}} 表示该错误的具体信息。每个错误同时也对应一个 HTTP 状态码和一个 gRPC 状态码，比如上述错误码对应了 HTTP 状态码 500(Internal Server Error)，gRPC 状态码未指定时由 HTTP 状态码推导。
 
 ## 错误码列表
 
 系统支持的错误码列表如下：
 
-| Identifier | Code | HTTP Code | Description |
-| ---------- | ---- | --------- | ----------- |
+| Identifier | Code | HTTP Code | gRPC Code | Description |
+| ---------- | ---- | --------- | --------- | ----------- |
 `
@@ -10,14 +10,15 @@ import (
 )
 
 // New return a std error.
+// The args may be marked by Safe or Redact, see Redacted.
 func New(format string, args ...interface{}) error {
-	return fmt.Errorf(format, args...)
+	return errorf(format, args...)
 }
 
 // NewWithStack return a error with stack.
 func NewWithStack(format string, args ...interface{}) error {
 	return &withStack{
-		fmt.Errorf(format, args...),
+		errorf(format, args...),
 		callers(),
 	}
 }
@@ -31,10 +32,12 @@ func WithStack(err error) error {
 
 	if wc := new(withCode); As(err, &wc) {
 		return &withCode{
-			err:   wc.err,
-			code:  wc.code,
-			cause: err,
-			stack: callers(),
+			err:      wc.err,
+			code:     wc.code,
+			cause:    err,
+			stack:    callers(),
+			registry: wc.registry,
+			coder:    wc.coder,
 		}
 	}
 	if e := new(withStack); As(err, &e) {
@@ -52,21 +55,29 @@ func WithStack(err error) error {
 // at the point Wrap is called, and the supplied message.
 // If err is nil, Wrap returns nil.
 func WrapStack(err error, message string) error {
+	return wrapStack(err, errorf(message))
+}
+
+// wrapStack is WrapStack with the message formatted already.
+func wrapStack(err, message error) error {
 	if err == nil {
 		return nil
 	}
 	if wc := new(withCode); As(err, &wc) {
 		return &withCode{
-			err:   fmt.Errorf(message),
-			code:  wc.code,
-			cause: err,
-			stack: callers(),
+			err:      message,
+			code:     wc.code,
+			cause:    err,
+			stack:    currentStackPolicy().capture(3, 0),
+			registry: wc.registry,
+			coder:    wc.coder,
 		}
 	}
 
 	messageErr := &withMessage{
-		cause: err,
-		msg:   message,
+		cause:    err,
+		msg:      message.Error(),
+		redacted: redactedText(message),
 	}
 	if ws := new(withStack); As(err, &ws) {
 		return messageErr
@@ -74,7 +85,7 @@ func WrapStack(err error, message string) error {
 
 	return &withStack{
 		messageErr,
-		callers(),
+		currentStackPolicy().capture(3, 0),
 	}
 }
 
@@ -83,7 +94,7 @@ func WrapStack(err error, message string) error {
 // at the point Wrapf is called, and the format specifier.
 // If err is nil, Wrapf returns nil.
 func WrapStackf(err error, format string, args ...interface{}) error {
-	return WrapStack(err, fmt.Sprintf(format, args...))
+	return wrapStack(err, errorf(format, args...))
 }
 
 type withStack struct {
@@ -107,15 +118,20 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			fmt.Fprintf(s, "%+v", w.Cause())
-			w.stack.Format(s, verb)
+			formatLog(s, verb, w.Cause())
+			// the frames shared with the stack of the cause are already printed
+			w.stack.formatElided(s, printedStack(w.Cause()))
 			return
 		}
 		if s.Flag('-') {
-			fmt.Fprintf(s, "%-v", w.Cause())
+			formatLog(s, verb, w.Cause())
 			w.stack.Format(s, verb)
 			return
 		}
+		if s.Flag('#') {
+			_, _ = io.WriteString(s, logText(w))
+			return
+		}
 
 		fallthrough
 	case 's':
@@ -126,19 +142,50 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 }
 
 // option for withCode
-type option func(*withCode)
+type option func(*codeOptions)
+
+// codeOptions are the options of NewWithCodeX, only used while creating the error.
+type codeOptions struct {
+	skipDepth int
+	args      []interface{}
+	registry  *Registry
+	policy    StackPolicy
+}
 
 // WithSkipDepth set skip depth.
 func WithSkipDepth(skipDepth int) option {
-	return func(w *withCode) {
-		w.skipDepth = skipDepth
+	return func(o *codeOptions) {
+		o.skipDepth = skipDepth
+	}
+}
+
+// WithArgs set the format args of the message of NewWithCodeX, which is a format then.
+// The args may be marked by Safe or Redact, see Redacted.
+func WithArgs(args ...interface{}) option {
+	return func(o *codeOptions) {
+		o.args = args
+	}
+}
+
+// WithRegistry set the registry of the code of the error, instead of DefaultRegistry.
+func WithRegistry(r *Registry) option {
+	return func(o *codeOptions) {
+		o.registry = r
+	}
+}
+
+// WithStackPolicy set the stack policy of the error instead of the global one.
+func WithStackPolicy(policy StackPolicy) option {
+	return func(o *codeOptions) {
+		o.policy = policy
 	}
 }
 
 // NewWithCode new error has default describe.
+// The args may be marked by Safe or Redact, see Redacted.
 func NewWithCode(code int, format string, args ...interface{}) error {
 	return &withCode{
-		err:   fmt.Errorf(format, args...),
+		err:   errorf(format, args...),
 		code:  code,
 		stack: callers(),
 	}
@@ -146,17 +193,18 @@ func NewWithCode(code int, format string, args ...interface{}) error {
 
 // NewWithCodeX new error with code with options.
 func NewWithCodeX(code int, message string, opts ...option) error {
-	w := &withCode{
-		err:   fmt.Errorf(message),
-		code:  code,
-		stack: callers(),
-	}
-
+	o := &codeOptions{policy: currentStackPolicy()}
 	for _, opt := range opts {
-		opt(w)
+		opt(o)
 	}
 
-	return w
+	return &withCode{
+		err:       errorf(message, o.args...),
+		code:      code,
+		stack:     o.policy.capture(2, o.skipDepth),
+		skipDepth: o.skipDepth,
+		registry:  o.registry,
+	}
 }
 
 // WrapC return an error annotating err with a stack trace and error code.
@@ -194,7 +242,7 @@ func WrapCode(err error, code int, message string) error {
 	}
 
 	return &withCode{
-		err:   fmt.Errorf(message),
+		err:   errorf(message),
 		code:  code,
 		cause: err,
 		stack: callers(),
@@ -207,7 +255,7 @@ func WrapCodef(err error, code int, format string, args ...interface{}) error {
 	}
 
 	return &withCode{
-		err:   fmt.Errorf(format, args...),
+		err:   errorf(format, args...),
 		code:  code,
 		cause: err,
 		stack: callers(),
@@ -221,6 +269,12 @@ type withCode struct {
 	*stack
 	// jumpDepth is the number of stack frames to skip when reporting
 	skipDepth int
+	// coder overrides the registered Coder of code, e.g. with the one received from the wire.
+	coder Coder
+	// remote reports whether the error was received from a remote peer.
+	remote bool
+	// registry is the registry of code, DefaultRegistry if nil.
+	registry *Registry
 }
 
 // Error return the externally-safe error message.
@@ -232,8 +286,22 @@ func (w *withCode) Cause() error { return w.cause }
 // Unwrap provides compatibility for Go 1.13 error chains.
 func (w *withCode) Unwrap() error { return w.cause }
 
+// lookup returns the Coder of w, preferring the one carried by w over the registered one.
+// The code is looked up in the registry of w, or in r if it has none.
+func (w *withCode) lookup(r *Registry) (Coder, bool) {
+	if w.coder != nil {
+		return w.coder, true
+	}
+
+	if w.registry != nil {
+		r = w.registry
+	}
+
+	return r.lookup(w.code)
+}
+
 // impl grpc func GRPCStatus() *Status
-func (w *withCode) GRPCStatus() *status.Status { return GRPCCodeStatus(w.code) }
+func (w *withCode) GRPCStatus() *status.Status { return GRPCStatus(w) }
 
 // WithMessage annotates err with a new message.
 // If err is nil, WithMessage returns nil.
@@ -249,21 +317,27 @@ func WithMessage(err error, message string) error {
 }
 
 // WithMessagef annotates err with the format specifier.
+// The args may be marked by Safe or Redact, see Redacted.
 // If err is nil, WithMessagef returns nil.
 func WithMessagef(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
 
+	message := errorf(format, args...)
+
 	return &withMessage{
-		cause: err,
-		msg:   fmt.Sprintf(format, args...),
+		cause:    err,
+		msg:      message.Error(),
+		redacted: redactedText(message),
 	}
 }
 
 type withMessage struct {
 	cause error
 	msg   string
+	// redacted is the redacted rendering of msg, if it differs.
+	redacted string
 }
 
 func (w *withMessage) Error() string { return w.msg }
@@ -272,13 +346,28 @@ func (w *withMessage) Cause() error  { return w.cause }
 // Unwrap provides compatibility for Go 1.13 error chains.
 func (w *withMessage) Unwrap() error { return w.cause }
 
+// redactedMsg returns the redacted rendering of msg.
+func (w *withMessage) redactedMsg() string {
+	if w.redacted != "" {
+		return w.redacted
+	}
+
+	return w.msg
+}
+
 // nolint: errcheck // WriteString could no check in pkg
 func (w *withMessage) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			fmt.Fprintf(s, "%+v\n", w.Cause())
-			io.WriteString(s, w.msg)
+			formatLog(s, verb, w.Cause())
+			io.WriteString(s, "\n")
+			io.WriteString(s, logText(w))
+
+			return
+		}
+		if s.Flag('-') || s.Flag('#') {
+			io.WriteString(s, logText(w))
 
 			return
 		}
@@ -17,28 +17,43 @@ func main() {
 		middlewarelog.GinMiddleware(
 			log.FromContextOrDiscard, middlewarelog.WithPayloadWhenError(),
 		),
+		Recovery,
 	)
 	r.GET("/user", GetUser)
 	_ = r.Run()
 }
 
+// Recovery renders the panics of the handlers as coded errors.
+func Recovery(c *gin.Context) {
+	err := errors.Try(func() error {
+		c.Next()
+		return nil
+	})
+	if err != nil {
+		_ = c.Error(err)
+		errors.WriteHTTP(c.Writer, c.Request, err)
+		c.Abort()
+		global.Logger().Errorf("handler panicked: %s%+v", errors.Redacted(err), errors.MergedStackTrace(err))
+	}
+}
+
 func GetUser(c *gin.Context) {
 	r := new(api.GetUserReq)
 	if err := c.BindQuery(r); err != nil {
 		err := errors.WithCode(err, code.ErrBind)
 		_ = c.Error(err) // 设置后会有统一日志输出
-		coder := errors.ParseCoder(err)
-		c.AbortWithStatusJSON(coder.HTTPStatus(), coder)
-		global.Logger().Errorf("bind query failed: %-v", err)
+		errors.WriteHTTP(c.Writer, c.Request, err)
+		c.Abort()
+		global.Logger().Errorf("bind query failed: %s", errors.Redacted(err))
 		return
 	}
 	svc := service.NewUserSvc()
 	reply, err := svc.GetUser(r)
 	if err != nil {
 		_ = c.Error(err)
-		coder := errors.ParseCoder(err)
-		c.JSON(coder.HTTPStatus(), coder)
-		global.Logger().Errorf("GetUser failed: %-v", err)
+		errors.WriteHTTP(c.Writer, c.Request, err)
+		c.Abort()
+		global.Logger().Errorf("GetUser failed: %s", errors.Redacted(err))
 		return
 	}
 	c.JSON(200, reply)
@@ -1,6 +1,6 @@
 package code
 
-//go:generate codegen -type=int
+//go:generate codegen -type=int -range exampleCodes -i18n i18n/zh.json
 //go:generate codegen -type=int -doc -output ../docs/error_code_generated.md
 
 // base: base errors.
@@ -23,6 +23,6 @@ const (
 	// ErrUserNotFound - 400: User Not Found.
 	ErrUserNotFound
 
-	// ErrUserDisabled - 400: User disabled.
+	// ErrUserDisabled - 400(FailedPrecondition): User disabled.
 	ErrUserDisabled
 )
@@ -2,16 +2,24 @@
 package code
 
 import "github.com/go-leo/errors"
-import "fmt"
+import "google.golang.org/grpc/codes"
 
 // init register error codes defines in this source code to `github.com/go-leo/errors`
 func init() {
-	register(ErrUnknown, 500, "Internal server error")
-	register(ErrBind, 400, "Error occurred while binding the request body to the struct")
-	register(ErrValidation, 400, "Validation failed")
-	register(ErrAccountAuthTypeInvalid, 400, "Account AuthType not support")
-	register(ErrUserNotFound, 400, "User Not Found")
-	register(ErrUserDisabled, 400, "User disabled")
+	registerIn(exampleCodes, ErrUnknown, 500, "Internal server error")
+	registerIn(exampleCodes, ErrBind, 400, "Error occurred while binding the request body to the struct")
+	registerIn(exampleCodes, ErrValidation, 400, "Validation failed")
+	registerIn(exampleCodes, ErrAccountAuthTypeInvalid, 400, "Account AuthType not support")
+	registerIn(exampleCodes, ErrUserNotFound, 400, "User Not Found")
+	registerGRPCIn(exampleCodes, ErrUserDisabled, 400, codes.FailedPrecondition, "User disabled")
+	errors.RegisterTranslations("zh", map[int]string{
+		ErrUnknown:                "服务器内部错误",
+		ErrBind:                   "请求参数绑定失败",
+		ErrValidation:             "请求参数校验失败",
+		ErrAccountAuthTypeInvalid: "不支持的账号认证类型",
+		ErrUserNotFound:           "用户不存在",
+		ErrUserDisabled:           "用户已被禁用",
+	})
 }
 
 // Internal server error
@@ -25,7 +33,7 @@ func IsErrUnknown(err error) bool {
 
 // Internal server error
 func NewErrUnknown(format string, args ...interface{}) error {
-	return errors.NewWithCodeX(ErrUnknown, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	return errors.NewWithCodeX(ErrUnknown, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
 
 // Error occurred while binding the request body to the struct
@@ -39,7 +47,7 @@ func IsErrBind(err error) bool {
 
 // Error occurred while binding the request body to the struct
 func NewErrBind(format string, args ...interface{}) error {
-	return errors.NewWithCodeX(ErrBind, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	return errors.NewWithCodeX(ErrBind, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
 
 // Validation failed
@@ -53,7 +61,7 @@ func IsErrValidation(err error) bool {
 
 // Validation failed
 func NewErrValidation(format string, args ...interface{}) error {
-	return errors.NewWithCodeX(ErrValidation, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	return errors.NewWithCodeX(ErrValidation, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
 
 // Account AuthType not support
@@ -67,7 +75,7 @@ func IsErrAccountAuthTypeInvalid(err error) bool {
 
 // Account AuthType not support
 func NewErrAccountAuthTypeInvalid(format string, args ...interface{}) error {
-	return errors.NewWithCodeX(ErrAccountAuthTypeInvalid, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	return errors.NewWithCodeX(ErrAccountAuthTypeInvalid, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
 
 // User Not Found
@@ -81,7 +89,7 @@ func IsErrUserNotFound(err error) bool {
 
 // User Not Found
 func NewErrUserNotFound(format string, args ...interface{}) error {
-	return errors.NewWithCodeX(ErrUserNotFound, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	return errors.NewWithCodeX(ErrUserNotFound, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
 
 // User disabled
@@ -95,5 +103,5 @@ func IsErrUserDisabled(err error) bool {
 
 // User disabled
 func NewErrUserDisabled(format string, args ...interface{}) error {
-	return errors.NewWithCodeX(ErrUserDisabled, fmt.Sprintf(format, args...), errors.WithSkipDepth(1))
+	return errors.NewWithCodeX(ErrUserDisabled, format, errors.WithSkipDepth(1), errors.WithArgs(args...))
 }
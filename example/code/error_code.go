@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-leo/errors"
 	"golang.org/x/exp/slices"
+	"google.golang.org/grpc/codes"
 )
 
 // ErrCode implements `panda/pkg/errors`.Coder interface.
@@ -50,8 +51,22 @@ func (coder ErrCode) HTTPStatus() int {
 	return coder.HTTP
 }
 
+// exampleCodes is the range of the codes of the example, the generated code
+// registers them through it, see codegen -range.
+var exampleCodes = errors.RegisterRange("example", 100000, 119999)
+
 //nolint: unparam // .
-func register(code int, httpStatus int, message string, refs ...string) {
+func registerIn(cr *errors.CodeRange, code int, httpStatus int, message string, refs ...string) {
+	cr.MustRegister(newErrCode(code, httpStatus, message, refs...))
+}
+
+// registerGRPCIn is like registerIn, but the gRPC code of the code is grpcCode
+// instead of the one derived from httpStatus.
+func registerGRPCIn(cr *errors.CodeRange, code int, httpStatus int, grpcCode codes.Code, message string, refs ...string) {
+	cr.MustRegister(errors.WithGRPCCode(newErrCode(code, httpStatus, message, refs...), grpcCode))
+}
+
+func newErrCode(code int, httpStatus int, message string, refs ...string) *ErrCode {
 	found := slices.Contains([]int{200, 400, 401, 403, 404, 500}, httpStatus)
 	if !found {
 		panic("http code not in `200, 400, 401, 403, 404, 500`")
@@ -62,12 +77,10 @@ func register(code int, httpStatus int, message string, refs ...string) {
 		reference = refs[0]
 	}
 
-	coder := &ErrCode{
+	return &ErrCode{
 		C:    code,
 		HTTP: httpStatus,
 		Ext:  message,
 		Ref:  reference,
 	}
-
-	errors.MustRegister(coder)
 }
@@ -8,6 +8,11 @@ import (
 )
 
 func Example() {
+	errors.SetPathMode(errors.PathImport)
+	errors.SetHideStdFrames(true)
+	defer errors.SetPathMode(errors.PathAbsolute)
+	defer errors.SetHideStdFrames(false)
+
 	err := getUser("")
 	fmt.Println(err)
 	fmt.Printf("%-v\n", err)
@@ -25,38 +30,36 @@ func Example() {
 
 	//Output:
 	//Account AuthType not support
-	// #0 (110001) Account AuthType not support, token  [/Users/litao/code/errors/example/examp_test.go:64 (github.com/go-leo/errors/example.getUser)]
-	// #0 (110001) Account AuthType not support, token  [/Users/litao/code/errors/example/examp_test.go:64 (github.com/go-leo/errors/example.getUser)]
+	// #0 (110001) Account AuthType not support, token  [github.com/go-leo/errors/example/examp_test.go:67 (github.com/go-leo/errors/example.getUser)]
+	// #0 (110001) Account AuthType not support, token  [github.com/go-leo/errors/example/examp_test.go:67 (github.com/go-leo/errors/example.getUser)]
+	// github.com/go-leo/errors/example.getUser
+	// 	github.com/go-leo/errors/example/examp_test.go:67
+	// github.com/go-leo/errors/example.Example
+	// 	github.com/go-leo/errors/example/examp_test.go:16
 	// datebase, connection error!
 	// datebase, connection error!
 	// github.com/go-leo/errors/example.getUserByID
-	// 	/Users/litao/code/errors/example/examp_test.go:82
+	// 	github.com/go-leo/errors/example/examp_test.go:85
 	// github.com/go-leo/errors/example.getUser
-	// 	/Users/litao/code/errors/example/examp_test.go:71
+	// 	github.com/go-leo/errors/example/examp_test.go:74
 	// github.com/go-leo/errors/example.Example
-	// 	/Users/litao/code/errors/example/examp_test.go:16
+	// 	github.com/go-leo/errors/example/examp_test.go:21
 	// datebase, connection error!
 	// github.com/go-leo/errors/example.getUserByID
-	// 	/Users/litao/code/errors/example/examp_test.go:82
+	// 	github.com/go-leo/errors/example/examp_test.go:85
 	// github.com/go-leo/errors/example.getUser
-	// 	/Users/litao/code/errors/example/examp_test.go:71
+	// 	github.com/go-leo/errors/example/examp_test.go:74
 	// github.com/go-leo/errors/example.Example
-	// 	/Users/litao/code/errors/example/examp_test.go:16
-	// testing.runExample
-	// 	/Users/litao/.go/current/src/testing/run_example.go:63
-	// testing.runExamples
-	// 	/Users/litao/.go/current/src/testing/example.go:44
-	// testing.(*M).Run
-	// 	/Users/litao/.go/current/src/testing/testing.go:1721
-	// main.main
-	// 	_testmain.go:49
-	// runtime.main
-	// 	/Users/litao/.go/current/src/runtime/proc.go:250
-	// runtime.goexit
-	// 	/Users/litao/.go/current/src/runtime/asm_amd64.s:1571
+	// 	github.com/go-leo/errors/example/examp_test.go:21
 	// Account AuthType not support
-	// #0 (110001) Account AuthType not support, uid  [/Users/litao/code/errors/example/examp_test.go:78 (github.com/go-leo/errors/example.getUserByID)]
-	// #0 (110001) Account AuthType not support, uid  [/Users/litao/code/errors/example/examp_test.go:78 (github.com/go-leo/errors/example.getUserByID)]
+	// #0 (110001) Account AuthType not support, uid  [github.com/go-leo/errors/example/examp_test.go:81 (github.com/go-leo/errors/example.getUserByID)]
+	// #0 (110001) Account AuthType not support, uid  [github.com/go-leo/errors/example/examp_test.go:81 (github.com/go-leo/errors/example.getUserByID)]
+	// github.com/go-leo/errors/example.getUserByID
+	// 	github.com/go-leo/errors/example/examp_test.go:81
+	// github.com/go-leo/errors/example.getUser
+	// 	github.com/go-leo/errors/example/examp_test.go:74
+	// github.com/go-leo/errors/example.Example
+	// 	github.com/go-leo/errors/example/examp_test.go:26
 }
 
 func getUser(token string) error {
@@ -10,9 +10,9 @@ type UserRepo struct{}
 
 func (ur *UserRepo) GetUser(uid int) (name string, err error) {
 	if uid <= 10 {
-		return "", code.NewErrUserDisabled("uid: %d", uid)
+		return "", code.NewErrUserDisabled("uid: %d", errors.Redact(uid))
 	} else if 10 < uid && uid <= 100 {
-		return "", code.NewErrUserNotFound("uid: %d", uid)
+		return "", code.NewErrUserNotFound("uid: %d", errors.Redact(uid))
 	} else {
 		e := errors.New("database conn failed!")
 		return "", errors.WithStack(e)
@@ -6,14 +6,21 @@ import (
 )
 
 func ExampleNewWithCode() {
+	SetPathMode(PathImport)
+	defer SetPathMode(PathAbsolute)
+	SetHideStdFrames(true)
+	defer SetHideStdFrames(false)
+
 	err := NewWithCode(ErrInvalidJSON, "id 1000")
 	fmt.Println(err)
 	fmt.Printf("%-v\n", err)
 	fmt.Printf("%+v\n", err)
 
 	// Output: Data is not valid JSON
-	//#0 (1001) Data is not valid JSON, id 1000 [/Users/litao/code/errors/example_test.go:9 (github.com/go-leo/errors.ExampleNewWithCode)]
-	//#0 (1001) Data is not valid JSON, id 1000 [/Users/litao/code/errors/example_test.go:9 (github.com/go-leo/errors.ExampleNewWithCode)]
+	//#0 (1001) Data is not valid JSON, id 1000 [github.com/go-leo/errors/example_test.go:14 (github.com/go-leo/errors.ExampleNewWithCode)]
+	//#0 (1001) Data is not valid JSON, id 1000 [github.com/go-leo/errors/example_test.go:14 (github.com/go-leo/errors.ExampleNewWithCode)]
+	//github.com/go-leo/errors.ExampleNewWithCode
+	//	github.com/go-leo/errors/example_test.go:14
 }
 
 func ExampleWrapC() {
@@ -53,7 +60,7 @@ func ExampledefaultCoder_HTTPStatus() {
 		err = WrapC(err, ErrLoadConfigFailed)
 	}
 
-	fmt.Println(codes[err.(*withCode).code].HTTPStatus())
+	fmt.Println(GetCoder(err.(*withCode).code).HTTPStatus())
 	// Output: 500
 }
 
@@ -74,6 +81,6 @@ func ExampleString() {
 		err = WrapC(err, ErrLoadConfigFailed)
 	}
 
-	fmt.Println(codes[err.(*withCode).code].String())
+	fmt.Println(GetCoder(err.(*withCode).code).String())
 	// Output: Load configuration file failed
 }
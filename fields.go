@@ -0,0 +1,127 @@
+package errors
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/status"
+)
+
+const badKey = "!BADKEY"
+
+// WithFields annotates err with key/value pairs.
+// kvs is a list of alternating keys and values; a key must be a string,
+// otherwise it is reported under the key "!BADKEY".
+// If err is nil, WithFields returns nil.
+func WithFields(err error, kvs ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(kvs)/2)
+	for len(kvs) > 0 {
+		key, ok := kvs[0].(string)
+		if !ok || len(kvs) == 1 {
+			fields[badKey] = kvs[0]
+			kvs = kvs[1:]
+
+			continue
+		}
+
+		fields[key] = kvs[1]
+		kvs = kvs[2:]
+	}
+
+	return &withFields{
+		cause:  err,
+		fields: fields,
+	}
+}
+
+// Fields returns the key/value pairs attached to err's chain by WithFields.
+// Fields closer to the top of the chain win over deeper fields with the same key.
+// The fields of the errors of an aggregate are collected too, the earlier errors
+// win over the later ones.
+// The metadata of a gRPC status carrying a *Status detail is read back as fields.
+func Fields(err error) map[string]interface{} {
+	fields := map[string]interface{}{}
+	collectFields(err, fields)
+
+	return fields
+}
+
+// collectFields adds the fields of err's chain missing from fields.
+func collectFields(err error, fields map[string]interface{}) {
+	add := func(k string, v interface{}) {
+		if _, ok := fields[k]; !ok {
+			fields[k] = v
+		}
+	}
+
+	for ; err != nil; err = Unwrap(err) {
+		switch e := err.(type) {
+		case *withFields:
+			for k, v := range e.fields {
+				add(k, v)
+			}
+		case *withCode, *joinError:
+			// GRPCStatus of our own errors is built from the fields.
+		case interface{ GRPCStatus() *status.Status }:
+			if d, ok := statusDetail(e.GRPCStatus()); ok {
+				for k, v := range d.Metadata {
+					add(k, v)
+				}
+			}
+		}
+
+		if e, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range e.Unwrap() {
+				collectFields(err, fields)
+			}
+
+			return
+		}
+	}
+}
+
+// metadata returns the fields of err's chain formatted as strings.
+func metadata(err error) map[string]string {
+	fields := Fields(err)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	md := make(map[string]string, len(fields))
+	for k, v := range fields {
+		md[k] = fmt.Sprint(v)
+	}
+
+	return md
+}
+
+type withFields struct {
+	cause  error
+	fields map[string]interface{}
+}
+
+func (w *withFields) Error() string { return w.cause.Error() }
+func (w *withFields) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withFields) Unwrap() error { return w.cause }
+
+// impl grpc func GRPCStatus() *Status
+func (w *withFields) GRPCStatus() *status.Status { return GRPCStatus(w) }
+
+// Format formats the error as its cause does.
+func (w *withFields) Format(state fmt.State, verb rune) {
+	w.formatChain(state, verb, w)
+}
+
+func (w *withFields) formatChain(state fmt.State, verb rune, root error) {
+	if f, ok := w.cause.(chainFormatter); ok {
+		f.formatChain(state, verb, root)
+		return
+	}
+
+	fmt.Fprintf(state, fmt.FormatString(state, verb), w.cause)
+}
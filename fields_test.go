@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithFields(t *testing.T) {
+	assert.Nil(t, WithFields(nil, "uid", 42))
+
+	err := NewWithCode(ErrUserNoRegister, "uid %d", 42)
+	err = WithFields(err, "uid", 42, "tenant", "acme")
+	err = WithMessage(err, "get user")
+	err = WithFields(err, "tenant", "other", 7, "odd")
+
+	assert.Equal(t, map[string]interface{}{
+		"uid":    42,
+		"tenant": "other",
+		badKey:   "odd",
+	}, Fields(err))
+	assert.Equal(t, ErrUserNoRegister, ParseCoder(err).Code())
+	assert.Empty(t, Fields(NewWithCode(ErrEOF, "eof")))
+}
+
+func TestWithFieldsFormat(t *testing.T) {
+	err := WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42)
+
+	assert.Equal(t, "user no register, go to create", err.Error())
+	assert.Equal(t, "user no register, go to create", fmt.Sprintf("%v", err))
+	assert.Equal(t, `[{"error":"user no register, go to create","metadata":{"uid":42}}]`, fmt.Sprintf("%#v", err))
+}
+
+func TestWithFieldsGRPCStatus(t *testing.T) {
+	err := WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42, "tenant", "acme")
+
+	s := GRPCStatus(err)
+	for _, detail := range s.Details() {
+		if d, ok := detail.(*Status); ok {
+			assert.Equal(t, map[string]string{"uid": "42", "tenant": "acme"}, d.Metadata)
+		}
+	}
+
+	// client side
+	ge := status.FromProto(s.Proto()).Err()
+	assert.Equal(t, map[string]interface{}{"uid": "42", "tenant": "acme"}, Fields(ge))
+}
+
+func TestWithFieldsJoin(t *testing.T) {
+	err := WithFields(Join(
+		WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42, "tenant", "acme"),
+		fmt.Errorf("wrapped: %w", WithFields(NewWithCode(ErrEOF, "eof"), "tenant", "other", "file", "a.txt")),
+	), "request", "r1")
+
+	assert.Equal(t, map[string]interface{}{
+		"request": "r1",
+		"uid":     42,
+		"tenant":  "acme",
+		"file":    "a.txt",
+	}, Fields(err))
+
+	assert.Equal(t, map[string]string{"request": "r1", "uid": "42", "tenant": "acme", "file": "a.txt"}, NewHTTPError(err).Metadata)
+	for _, detail := range GRPCStatus(err).Details() {
+		if d, ok := detail.(*Status); ok {
+			assert.Equal(t, "acme", d.Metadata["tenant"])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"path"
+	runtimedebug "runtime/debug"
+	"strings"
+)
+
+// PathMode controls how the source file paths of printed frames are rendered.
+type PathMode int
+
+const (
+	// PathAbsolute renders the absolute build path, e.g.
+	// /home/lk/go/src/github.com/go-leo/errors/errors.go.
+	PathAbsolute PathMode = iota
+	// PathImport renders the path relative to the module root or GOPATH, that
+	// is the import path of the package followed by the file name, e.g.
+	// github.com/go-leo/errors/errors.go. It doesn't depend on the build machine.
+	// The import path of package main is the main package path of the build info,
+	// it stays "main" if unknown, e.g. for go run of files and the test binaries.
+	PathImport
+	// PathBase renders the file name only, e.g. errors.go.
+	PathBase
+)
+
+var (
+	pathMode       = PathAbsolute
+	hideStdFrames  = false
+	hiddenPackages []string
+)
+
+// mainPackage is the import path of package main, e.g. github.com/go-leo/errors/example/cmd.
+var mainPackage = func() string {
+	bi, ok := runtimedebug.ReadBuildInfo()
+	if !ok || bi.Path == "" || bi.Path == "command-line-arguments" || strings.HasSuffix(bi.Path, ".test") {
+		return "main"
+	}
+
+	return bi.Path
+}()
+
+// stdPackages are the packages whose frames are hidden by SetHideStdFrames.
+var stdPackages = []string{"runtime", "testing", "reflect"}
+
+// SetPathMode set how the source file paths of printed frames are rendered.
+func SetPathMode(mode PathMode) {
+	pathMode = mode
+}
+
+// SetHideStdFrames set whether the frames of the runtime, testing and reflect
+// packages, and of the generated test main, are hidden from printed stacks and
+// StackTrace.
+func SetHideStdFrames(hide bool) {
+	hideStdFrames = hide
+}
+
+// HidePackages hides the frames of the functions of the packages with any of
+// prefixes, e.g. middlewares or generated code, from printed stacks and StackTrace.
+// It replaces the prefixes of the previous call.
+func HidePackages(prefixes ...string) {
+	hiddenPackages = prefixes
+}
+
+// visible reports whether sym is not hidden by the frame filters.
+func (sym symbol) visible() bool {
+	if hideStdFrames {
+		if path.Base(sym.file) == "_testmain.go" {
+			return false
+		}
+
+		pkg := packagePath(sym.function)
+		for _, std := range stdPackages {
+			if pkg == std || strings.HasPrefix(pkg, std+"/") {
+				return false
+			}
+		}
+	}
+
+	for _, prefix := range hiddenPackages {
+		if strings.HasPrefix(sym.function, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// path returns the source file path of sym rendered with the path mode.
+func (sym symbol) path() string {
+	switch pathMode {
+	case PathImport:
+		pkg := strings.TrimSuffix(packagePath(sym.function), "_test")
+		if pkg == "main" {
+			pkg = mainPackage
+		}
+		if pkg != "" {
+			return pkg + "/" + path.Base(sym.file)
+		}
+	case PathBase:
+		return path.Base(sym.file)
+	}
+
+	return sym.file
+}
+
+// visibleSymbols returns the symbols of syms which are not hidden.
+func visibleSymbols(syms []symbol) []symbol {
+	ret := syms[:0:0]
+	for _, sym := range syms {
+		if sym.visible() {
+			ret = append(ret, sym)
+		}
+	}
+
+	return ret
+}
+
+// packagePath returns the import path of the package of a function name
+// reported by runtime.Frame, e.g. github.com/go-leo/errors of
+// github.com/go-leo/errors.(*withCode).Format.
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+
+	return function[:slash+1+dot]
+}
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackagePath(t *testing.T) {
+	assert.Equal(t, "github.com/go-leo/errors", packagePath("github.com/go-leo/errors.(*withCode).Format"))
+	assert.Equal(t, "github.com/go-leo/errors_test", packagePath("github.com/go-leo/errors_test.TestX.func1"))
+	assert.Equal(t, "runtime", packagePath("runtime.goexit"))
+	assert.Equal(t, "main", packagePath("main.main"))
+	assert.Equal(t, "", packagePath("unknown"))
+}
+
+func TestSetPathMode(t *testing.T) {
+	defer SetPathMode(PathAbsolute)
+
+	sym := symbol{function: "github.com/go-leo/errors.TestSetPathMode", file: "/src/errors/filter_test.go", line: 1}
+	assert.Equal(t, "/src/errors/filter_test.go", sym.path())
+
+	SetPathMode(PathImport)
+	assert.Equal(t, "github.com/go-leo/errors/filter_test.go", sym.path())
+
+	sym.function = "github.com/go-leo/errors_test.TestSetPathMode"
+	assert.Equal(t, "github.com/go-leo/errors/filter_test.go", sym.path())
+
+	SetPathMode(PathBase)
+	assert.Equal(t, "filter_test.go", sym.path())
+
+	// package main is rendered with the main package path of the build
+	SetPathMode(PathImport)
+	defer func(pkg string) { mainPackage = pkg }(mainPackage)
+	main := symbol{function: "main.main", file: "/src/app/cmd/server/main.go", line: 1}
+	assert.Equal(t, "main/main.go", main.path())
+	mainPackage = "github.com/acme/app/cmd/server"
+	assert.Equal(t, "github.com/acme/app/cmd/server/main.go", main.path())
+
+	SetPathMode(PathImport)
+	err := NewWithStack("trimmed")
+	assert.Contains(t, fmt.Sprintf("%+v", err), "\n\tgithub.com/go-leo/errors/filter_test.go:")
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "\n\t/")
+}
+
+func TestSetHideStdFrames(t *testing.T) {
+	defer SetHideStdFrames(false)
+
+	err := NewWithStack("hidden")
+	assert.Contains(t, fmt.Sprintf("%+v", err), "\ntesting.tRunner\n")
+
+	SetHideStdFrames(true)
+	trace := fmt.Sprintf("%+v", err)
+	assert.NotContains(t, trace, "\ntesting.")
+	assert.NotContains(t, trace, "\nruntime.")
+	assert.Contains(t, trace, "\ngithub.com/go-leo/errors.TestSetHideStdFrames\n")
+
+	for _, f := range err.(interface{ StackTrace() StackTrace }).StackTrace() {
+		assert.False(t, strings.HasPrefix(f.name(), "testing."), f.name())
+	}
+}
+
+func TestHidePackages(t *testing.T) {
+	defer HidePackages()
+
+	var err error
+	func() {
+		err = NewWithCode(ErrInvalidJSON, "hidden caller")
+	}()
+	assert.Contains(t, fmt.Sprintf("%-v", err), "(github.com/go-leo/errors.TestHidePackages.func1)]")
+
+	HidePackages("github.com/go-leo/errors.TestHidePackages.")
+	assert.Contains(t, fmt.Sprintf("%-v", err), "(github.com/go-leo/errors.TestHidePackages)]")
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "TestHidePackages.func1")
+}
@@ -26,61 +26,110 @@ type formatInfo struct {
 // Flags:
 //      #      JSON formatted output, useful for logging
 //      -      Output caller details, useful for troubleshooting
-//      +      Output full error stack details, useful for debugging
+//      +      Output full error stack details, useful for debugging, followed by
+//             the stacks of the chain from the innermost outwards, the frames each
+//             stack shares with the one printed before it are elided
+//
+// The flags are log formats: the internal messages are redacted, see SetLogRedacted.
+// The errors of an aggregate created by Join are flattened into the chain in order.
+// The JSON output carries the fields attached by WithFields as "metadata" on its
+// first element.
 //
 // Examples:
-//      %s:    error for internal read B
-//      %v:    error for internal read B
-//      %-v:   error for internal read B - #0 [/home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)] (#100102) Internal Server Error
-//      %+v:   error for internal read B - #0 [/home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)] (#100102) Internal Server Error; error for internal read A - #1 [/home/lk/workspace/golang/src/github.com/panda/iam/main.go:35 (main.newErrorB)] (#100104) Validation failed
-//      %#v:   [{"error":"error for internal read B"}]
-//      %#-v:  [{"caller":"#0 /home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)","error":"error for internal read B","message":"(#100102) Internal Server Error"}]
-//      %#+v:  [{"caller":"#0 /home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)","error":"error for internal read B","message":"(#100102) Internal Server Error"},{"caller":"#1 /home/lk/workspace/golang/src/github.com/panda/iam/main.go:35 (main.newErrorB)","error":"error for internal read A","message":"(#100104) Validation failed"}]
+//      %s:    Internal Server Error
+//      %v:    Internal Server Error
+//      %-v:   #1 (100102) Internal Server Error, error for internal read B [/home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)]
+//      %+v:   #1 (100102) Internal Server Error, error for internal read B [/home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)]#0 ; (100104) Validation failed, error for internal read A [/home/lk/workspace/golang/src/github.com/panda/iam/main.go:35 (main.newErrorA)]
+//             main.newErrorA
+//             	/home/lk/workspace/golang/src/github.com/panda/iam/main.go:35
+//             main.main
+//             	/home/lk/workspace/golang/src/github.com/panda/iam/main.go:11
+//             runtime.main
+//             	/usr/local/go/src/runtime/proc.go:250
+//             runtime.goexit
+//             	/usr/local/go/src/runtime/asm_amd64.s:1598
+//             main.main
+//             	/home/lk/workspace/golang/src/github.com/panda/iam/main.go:12
+//             ... 2 frames elided
+//      %#v:   [{"error":"Internal Server Error"}]
+//      %#-v:  [{"caller":"#1 /home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)","code":100102,"error":"error for internal read B","message":"Internal Server Error"}]
+//      %#+v:  [{"caller":"#1 /home/lk/workspace/golang/src/github.com/panda/iam/main.go:12 (main.main)","code":100102,"error":"error for internal read B","message":"Internal Server Error"},{"caller":"#0 /home/lk/workspace/golang/src/github.com/panda/iam/main.go:35 (main.newErrorA)","code":100104,"error":"error for internal read A","message":"Validation failed"}]
+//
+// The internal messages "error for internal read A" and "error for internal read B"
+// are shown as with SetLogRedacted(false).
 func (w *withCode) Format(state fmt.State, verb rune) {
+	w.formatChain(state, verb, w)
+}
+
+// chainFormatter is implemented by errors which format their whole chain.
+// root is the outermost error being formatted, it provides the fields.
+type chainFormatter interface {
+	formatChain(state fmt.State, verb rune, root error)
+}
+
+func (w *withCode) formatChain(state fmt.State, verb rune, root error) {
 	switch verb {
 	case 'v':
-		str := bytes.NewBuffer([]byte{})
-		jsonData := []map[string]interface{}{}
-
-		var (
-			flagDetail bool
-			flagTrace  bool
-			modeJSON   bool
-		)
-		if state.Flag('#') {
-			modeJSON = true
-		}
-		if state.Flag('-') {
-			flagDetail = true
-		}
+		errs := list(w)
+		limit := 1
 		if state.Flag('+') {
-			flagTrace = true
+			limit = len(errs)
 		}
 
-		sep := ""
-		errs := list(w)
-		length := len(errs)
+		formatChain(state, root, errs, limit)
+	default:
+		finfo := buildFormatInfo(w, false)
+		// Externally-safe error message
+		fmt.Fprintf(state, finfo.message)
+	}
+}
+
+// formatChain writes the first limit errors of errs according to the flags of state.
+// The JSON output carries the fields of root on its first element.
+func formatChain(state fmt.State, root error, errs []error, limit int) {
+	str := bytes.NewBuffer([]byte{})
+	jsonData := []map[string]interface{}{}
+
+	var (
+		flagDetail bool
+		flagTrace  bool
+		modeJSON   bool
+	)
+	if state.Flag('#') {
+		modeJSON = true
+	}
+	if state.Flag('-') {
+		flagDetail = true
+	}
+	if state.Flag('+') {
+		flagTrace = true
+	}
 
-		for k, e := range errs {
-			finfo := buildFormatInfo(e)
-			jsonData, str = format(length-k-1, jsonData, str, finfo, sep, flagDetail, flagTrace, modeJSON)
-			sep = "; "
+	sep := ""
+	length := len(errs)
 
-			if !flagTrace {
-				break
-			}
-		}
+	// the detail and JSON formats are log sinks
+	redact := logRedacted && (flagDetail || flagTrace || modeJSON)
+
+	for k, e := range errs[:limit] {
+		finfo := buildFormatInfo(e, redact)
+		jsonData, str = format(length-k-1, jsonData, str, finfo, sep, flagDetail, flagTrace, modeJSON)
+		sep = "; "
+	}
 
-		if modeJSON {
-			byts, _ := json.Marshal(jsonData)
-			str.Write(byts)
+	if modeJSON {
+		if fields := Fields(root); len(fields) > 0 && len(jsonData) > 0 {
+			jsonData[0]["metadata"] = fields
 		}
 
-		fmt.Fprintf(state, "%s", strings.Trim(str.String(), "\r\n\t"))
-	default:
-		finfo := buildFormatInfo(w)
-		// Externally-safe error message
-		fmt.Fprintf(state, finfo.message)
+		byts, _ := json.Marshal(jsonData)
+		str.Write(byts)
+	}
+
+	fmt.Fprintf(state, "%s", strings.Trim(str.String(), "\r\n\t"))
+
+	if flagTrace && !modeJSON {
+		formatStacks(state, errs[:limit])
 	}
 }
 
@@ -98,13 +147,12 @@ func format(k int, jsonData []map[string]interface{}, str *bytes.Buffer, finfo *
 
 			caller := fmt.Sprintf("#%d", k)
 
-			if finfo.stack != nil {
-				f := Frame((*finfo.stack)[0])
+			if sym, ok := finfo.stack.caller(finfo.skipDepth); ok {
 				caller = fmt.Sprintf("%s %s:%d (%s)",
 					caller,
-					f.file(),
-					f.line(),
-					f.name(),
+					sym.path(),
+					sym.line,
+					sym.function,
 				)
 			}
 
@@ -116,17 +164,16 @@ func format(k int, jsonData []map[string]interface{}, str *bytes.Buffer, finfo *
 		jsonData = append(jsonData, data)
 	} else {
 		if flagDetail || flagTrace {
-			if finfo.stack != nil && len(*finfo.stack) > finfo.skipDepth {
-				f := Frame((*finfo.stack)[finfo.skipDepth])
+			if sym, ok := finfo.stack.caller(finfo.skipDepth); ok {
 				fmt.Fprintf(str, "#%d %s(%d) %s, %s [%s:%d (%s)]",
 					k,
 					sep,
 					finfo.code,
 					finfo.message,
 					finfo.err,
-					f.file(),
-					f.line(),
-					f.name(),
+					sym.path(),
+					sym.line,
+					sym.function,
 				)
 			} else {
 				fmt.Fprintf(str, "#%d %s(%d) %s, %s [%s]", k, sep, finfo.code, finfo.message, finfo.err, finfo.message)
@@ -140,14 +187,23 @@ func format(k int, jsonData []map[string]interface{}, str *bytes.Buffer, finfo *
 }
 
 // list will convert the error stack into a simple array.
+// The errors of an aggregate are flattened in order.
 func list(e error) []error {
 	ret := []error{}
 
 	if e != nil {
-		if w, ok := e.(interface{ Unwrap() error }); ok {
+		switch w := e.(type) {
+		case *withFields:
+			// fields are not a message of their own
+			ret = append(ret, list(w.cause)...)
+		case interface{ Unwrap() error }:
 			ret = append(ret, e)
 			ret = append(ret, list(w.Unwrap())...)
-		} else {
+		case interface{ Unwrap() []error }:
+			for _, err := range w.Unwrap() {
+				ret = append(ret, list(err)...)
+			}
+		default:
 			ret = append(ret, e)
 		}
 	}
@@ -155,33 +211,42 @@ func list(e error) []error {
 	return ret
 }
 
-func buildFormatInfo(e error) *formatInfo {
+// buildFormatInfo returns the information of e, with its redacted messages if redact is true.
+func buildFormatInfo(e error, redact bool) *formatInfo {
 	var finfo *formatInfo
 
+	text := func(err error) string {
+		if redact {
+			return redactedText(err)
+		}
+
+		return err.Error()
+	}
+
 	switch err := e.(type) {
 	case *withCode:
-		coder, ok := codes[err.code]
+		coder, ok := err.lookup(registryOf(err, DefaultRegistry))
 		if !ok {
 			coder = UnknownCoder
 		}
 
 		extMsg := coder.String()
 		if extMsg == "" {
-			extMsg = err.err.Error()
+			extMsg = text(err.err)
 		}
 
 		finfo = &formatInfo{
 			code:      coder.Code(),
 			message:   extMsg,
-			err:       err.err.Error(),
+			err:       text(err.err),
 			stack:     err.stack,
 			skipDepth: err.skipDepth,
 		}
 	default:
 		finfo = &formatInfo{
 			code:    UnknownCoder.Code(),
-			message: err.Error(),
-			err:     err.Error(),
+			message: text(err),
+			err:     text(err),
 		}
 	}
 
@@ -0,0 +1,154 @@
+package errors
+
+import (
+	stderrors "errors"
+	"strings"
+
+	gcodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// legacyStatusName is the full name of the *Status detail sent by the versions
+// before its proto package was renamed from "errors" to "leo.errors.v1".
+const legacyStatusName = "errors.Status"
+
+// FromGRPC converts an error returned by a gRPC call back into a coded error.
+// The code, HTTP status, reference, message and metadata are read from the
+// *Status detail of the status into a Coder of its own, so ParseCoder never
+// returns nil for it, even if the code is not registered locally.
+// The gRPC code of the status is kept as the one of the Coder, see GRPCCoder.
+// A status without *Status detail is converted into an error of UnknownCoder's
+// code whose HTTP status is mapped from the gRPC code.
+// Errors which are not gRPC status errors, or are already coded, are returned as is.
+// If err is nil, FromGRPC returns nil.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := lookupCoder(err, DefaultRegistry); ok {
+		return err
+	}
+
+	// a local error of a code not registered is coded as well, GRPCStatus would
+	// turn it into a remote one losing its stack
+	if wc := new(withCode); As(err, &wc) {
+		return err
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return newRemote(err, receivedCoder(s), s.Message(), statusMetadata(s))
+}
+
+// receivedCoder returns the Coder of a status received from a remote peer: the one
+// described by its *Status detail, or else one of UnknownCoder's code whose HTTP
+// status is mapped from the gRPC code. The gRPC code of s is kept either way.
+func receivedCoder(s *status.Status) Coder {
+	if coder, ok := statusCoder(s); ok {
+		return coder
+	}
+
+	return remoteCoder(defaultCoder{UnknownCoder.Code(), FromGRPCCode(s.Code()), s.Message(), ""}, s.Code())
+}
+
+// receivedStatus returns the status of the first gRPC status error of err's chain
+// which is not one of the errors of this package, e.g. an error returned by a
+// gRPC call which is forwarded as is or wrapped by WithMessage.
+func receivedStatus(err error) (*status.Status, bool) {
+	for ; err != nil; err = Unwrap(err) {
+		switch e := err.(type) {
+		case *withCode, *withFields, *joinError:
+			// their GRPCStatus is built by GRPCStatus
+		case interface{ GRPCStatus() *status.Status }:
+			return e.GRPCStatus(), true
+		}
+	}
+
+	return nil, false
+}
+
+// IsRemote reports whether any error in err's chain was received from a remote peer.
+func IsRemote(err error) bool {
+	for ; err != nil; err = Unwrap(err) {
+		if wc, ok := err.(*withCode); ok && wc.remote {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newRemote returns a coded error received from a remote peer.
+func newRemote(cause error, coder Coder, message string, md map[string]string) error {
+	var err error = &withCode{
+		err:    stderrors.New(message),
+		code:   coder.Code(),
+		cause:  cause,
+		coder:  coder,
+		remote: true,
+	}
+
+	if len(md) > 0 {
+		kvs := make([]interface{}, 0, len(md)*2)
+		for k, v := range md {
+			kvs = append(kvs, k, v)
+		}
+
+		err = WithFields(err, kvs...)
+	}
+
+	return err
+}
+
+// statusCoder returns the Coder described by the *Status detail of s.
+func statusCoder(s *status.Status) (Coder, bool) {
+	if d, ok := statusDetail(s); ok {
+		return remoteCoder(defaultCoder{int(d.Code), int(d.Http), s.Message(), d.Ref}, s.Code()), true
+	}
+
+	return nil, false
+}
+
+// remoteCoder returns coder with the gRPC code of a received status, if it is not
+// the one derived from the HTTP status of coder.
+func remoteCoder(coder defaultCoder, code gcodes.Code) Coder {
+	if code == ToGRPCCode(coder.HTTPStatus()) {
+		return coder
+	}
+
+	return WithGRPCCode(coder, code)
+}
+
+// statusMetadata returns the metadata of the *Status detail of s.
+func statusMetadata(s *status.Status) map[string]string {
+	if d, ok := statusDetail(s); ok {
+		return d.Metadata
+	}
+
+	return nil
+}
+
+// statusDetail returns the *Status detail of s. The legacy "errors.Status" detail
+// of the peers of older versions is accepted as well, it has the same fields.
+func statusDetail(s *status.Status) (*Status, bool) {
+	name := string((*Status)(nil).ProtoReflect().Descriptor().FullName())
+
+	for _, detail := range s.Proto().GetDetails() {
+		url := detail.GetTypeUrl()
+		if n := url[strings.LastIndexByte(url, '/')+1:]; n != name && n != legacyStatusName {
+			continue
+		}
+
+		d := new(Status)
+		if err := proto.Unmarshal(detail.GetValue(), d); err == nil {
+			return d, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	gcodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestFromGRPC(t *testing.T) {
+	assert.Nil(t, FromGRPC(nil))
+
+	local := NewWithCode(ErrUserNoRegister, "uid %d", 42)
+	assert.Equal(t, local, FromGRPC(local))
+	assert.False(t, IsRemote(local))
+
+	std := fmt.Errorf("std error")
+	assert.Equal(t, std, FromGRPC(std))
+
+	err := FromGRPC(GRPCStatus(WithFields(local, "uid", 42)).Err())
+	assert.True(t, IsRemote(err))
+	assert.True(t, IsRemote(WithMessage(err, "call user service")))
+	assert.True(t, IsCode(err, ErrUserNoRegister))
+	assert.Equal(t, "user no register, go to create", err.Error())
+	assert.Equal(t, map[string]interface{}{"uid": "42"}, Fields(err))
+	assert.Equal(t, gcodes.InvalidArgument, status.Code(err))
+}
+
+func TestFromGRPCUnregistered(t *testing.T) {
+	s, _ := status.New(gcodes.NotFound, "order not found").
+		WithDetails(&Status{Code: 424242, Http: http.StatusNotFound, Ref: "https://example.com/424242"})
+
+	coder := ParseCoder(s.Err())
+	assert.NotNil(t, coder)
+	assert.Equal(t, 424242, coder.Code())
+
+	err := FromGRPC(s.Err())
+	coder = ParseCoder(err)
+	assert.Equal(t, 424242, coder.Code())
+	assert.Equal(t, http.StatusNotFound, coder.HTTPStatus())
+	assert.Equal(t, "order not found", coder.String())
+	assert.Equal(t, "https://example.com/424242", coder.Reference())
+	assert.Equal(t, "order not found", fmt.Sprintf("%v", err))
+
+	// the rehydrated error is sent on unchanged
+	assert.Equal(t, s.Proto().String(), GRPCStatus(err).Proto().String())
+}
+
+func TestFromGRPCLocalUnregistered(t *testing.T) {
+	local := NewWithCode(424243, "order %d not found", 42)
+
+	// a local error is returned as is, even if its code is not registered
+	for _, err := range []error{local, WithMessage(local, "get order")} {
+		got := FromGRPC(err)
+		assert.Equal(t, err, got)
+		assert.False(t, IsRemote(got))
+		assert.NotNil(t, MergedStackTrace(got))
+	}
+}
+
+func TestFromGRPCLegacyStatus(t *testing.T) {
+	// the detail sent by the versions before the proto package was renamed
+	value, _ := proto.Marshal(&Status{Code: 424242, Http: http.StatusNotFound, Metadata: map[string]string{"order": "o1"}})
+	s := status.FromProto(&spb.Status{
+		Code:    int32(gcodes.NotFound),
+		Message: "order not found",
+		Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/errors.Status", Value: value}},
+	})
+
+	assert.Equal(t, 424242, ParseCoder(s.Err()).Code())
+	assert.Equal(t, map[string]interface{}{"order": "o1"}, Fields(s.Err()))
+
+	err := FromGRPC(s.Err())
+	assert.True(t, IsCode(err, 424242))
+	assert.Equal(t, http.StatusNotFound, ParseCoder(err).HTTPStatus())
+	assert.Equal(t, map[string]interface{}{"order": "o1"}, Fields(err))
+}
+
+func TestFromGRPCWithoutDetail(t *testing.T) {
+	err := FromGRPC(status.Error(gcodes.NotFound, "not found"))
+
+	coder := ParseCoder(err)
+	assert.True(t, IsRemote(err))
+	assert.Equal(t, UnknownCoder.Code(), coder.Code())
+	assert.Equal(t, http.StatusNotFound, coder.HTTPStatus())
+	assert.Equal(t, "not found", coder.String())
+}
+
+func TestGRPCCoder(t *testing.T) {
+	r := NewRegistry("")
+	r.Register(WithGRPCCode(defaultCoder{100001, http.StatusBadRequest, "Precondition failed", ""}, gcodes.FailedPrecondition))
+	r.Register(WithGRPCCode(defaultCoder{100002, http.StatusConflict, "Exists", ""}, gcodes.AlreadyExists))
+	r.Register(defaultCoder{100003, http.StatusConflict, "Conflict", ""})
+
+	err := WithMessage(NewWithCodeX(100001, "user disabled", WithRegistry(r)), "login")
+	assert.Equal(t, gcodes.FailedPrecondition, GRPCStatus(err).Code())
+	assert.Equal(t, gcodes.FailedPrecondition, LocalizedGRPCStatus(err, "zh").Code())
+	assert.Equal(t, gcodes.AlreadyExists, r.GRPCCodeStatus(100002).Code())
+	assert.Equal(t, gcodes.Aborted, r.GRPCCodeStatus(100003).Code())
+	assert.Equal(t, http.StatusBadRequest, ParseCoder(err).HTTPStatus())
+	assert.Equal(t, "AlreadyExists", r.Catalog()[1].GRPC)
+	assert.Equal(t, "Aborted", r.Catalog()[2].GRPC)
+
+	// the explicit gRPC code survives the wire
+	remote := FromGRPC(GRPCStatus(err).Err())
+	assert.Equal(t, gcodes.FailedPrecondition, GRPCStatus(WithStack(remote)).Code())
+
+	data, _ := MarshalJSON(err)
+	decoded, _ := UnmarshalJSON(data)
+	assert.Equal(t, gcodes.FailedPrecondition, GRPCStatus(decoded).Code())
+	assert.Equal(t, gcodes.FailedPrecondition, GRPCStatus(FromProto(ToProto(err))).Code())
+
+	// codes loaded with a gRPC code are GRPCCoders too
+	assert.NoError(t, r.LoadCoders(strings.NewReader(`[{"code": 100004, "http": 400, "message": "Out", "grpc": "OutOfRange"}]`), CatalogJSON))
+	assert.Equal(t, gcodes.OutOfRange, r.GRPCCodeStatus(100004).Code())
+}
+
+func TestGRPCCoderLocalize(t *testing.T) {
+	coder := WithGRPCCode(localizedCoder{defaultCoder{100001, http.StatusBadRequest, "Invalid", ""}}, gcodes.OutOfRange)
+
+	message, ok := coder.(Localizer).Localize("fr")
+	assert.True(t, ok)
+	assert.Equal(t, "Erreur de configuration", message)
+
+	_, ok = WithGRPCCode(defaultCoder{100001, http.StatusBadRequest, "Invalid", ""}, gcodes.OutOfRange).(Localizer).Localize("fr")
+	assert.False(t, ok)
+}
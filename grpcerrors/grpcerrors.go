@@ -0,0 +1,146 @@
+// Package grpcerrors provides gRPC interceptors which carry the coded errors of
+// `github.com/go-leo/errors` across the wire.
+//
+// The server interceptors convert every returned error through errors.GRPCStatus,
+// so the client only receives the external message of the error's Coder, while
+// the internal form is handed to a logger. In debug mode, see errors.SetDebug, the
+// internal message and the stack are sent as well. The client interceptors rehydrate
+// the received statuses with errors.FromGRPC, so errors.IsCode and errors.ParseCoder
+// work on the client side.
+package grpcerrors
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/go-leo/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Logger logs the error returned by the handler of fullMethod before it is
+// converted into a gRPC status.
+type Logger func(ctx context.Context, fullMethod string, err error)
+
+// DefaultLogger logs the redacted internal message of err, see errors.Redacted,
+// and its stack with the standard logger.
+func DefaultLogger(_ context.Context, fullMethod string, err error) {
+	log.Printf("%s: %s%+v", fullMethod, errors.Redacted(err), errors.MergedStackTrace(err))
+}
+
+type options struct {
+	logger Logger
+}
+
+// Option configures the server interceptors.
+type Option func(*options)
+
+// WithLogger sets the logger of returned errors, nil disables logging.
+// Defaults to DefaultLogger.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{logger: DefaultLogger}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// toStatus logs err and converts it into a gRPC status error, whose message is
+// translated with the "accept-language" metadata of the incoming context.
+// Status errors without Coder, e.g. status.Error(codes.NotFound, ""), are sent as is.
+func (o *options) toStatus(ctx context.Context, fullMethod string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if o.logger != nil {
+		o.logger(ctx, fullMethod, err)
+	}
+
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok && errors.ParseCoder(err) == errors.UnknownCoder {
+		return status.Convert(err).Err()
+	}
+
+	return errors.LocalizedGRPCStatus(err, acceptLanguage(ctx)).Err()
+}
+
+// acceptLanguage returns the "accept-language" metadata of the incoming context.
+func acceptLanguage(ctx context.Context) string {
+	return strings.Join(metadata.ValueFromIncomingContext(ctx, "accept-language"), ",")
+}
+
+// UnaryServerInterceptor returns a server interceptor which converts the errors
+// returned by unary handlers into gRPC statuses.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		return resp, o.toStatus(ctx, info.FullMethod, err)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor which converts the errors
+// returned by stream handlers into gRPC statuses.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+
+		return o.toStatus(ss.Context(), info.FullMethod, err)
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor which rehydrates the
+// errors of unary calls with errors.FromGRPC.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		return errors.FromGRPC(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor which rehydrates the
+// errors of streaming calls with errors.FromGRPC.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, errors.FromGRPC(err)
+		}
+
+		return &clientStream{cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return errors.FromGRPC(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return errors.FromGRPC(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return errors.FromGRPC(s.ClientStream.CloseSend())
+}
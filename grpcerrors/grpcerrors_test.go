@@ -0,0 +1,184 @@
+package grpcerrors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-leo/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const errUserNotFound = 2000
+
+type coder struct{}
+
+func (coder) HTTPStatus() int   { return http.StatusNotFound }
+func (coder) String() string    { return "User not found" }
+func (coder) Reference() string { return "" }
+func (coder) Code() int         { return errUserNotFound }
+
+func init() {
+	errors.Register(coder{})
+	errors.RegisterTranslations("zh", map[int]string{errUserNotFound: "用户不存在"})
+}
+
+// testService returns err from both of its methods. The stream sends one
+// message before failing.
+type testService struct {
+	err error
+}
+
+var testServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcerrors.Test",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Unary",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, srv.(*testService).err
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcerrors.Test/Unary"}
+
+			return interceptor(ctx, in, info, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Stream",
+		ServerStreams: true,
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			if err := stream.SendMsg(new(emptypb.Empty)); err != nil {
+				return err
+			}
+
+			return srv.(*testService).err
+		},
+	}},
+}
+
+func dial(t *testing.T, svc *testService, logger Logger) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(WithLogger(logger))),
+		grpc.StreamInterceptor(StreamServerInterceptor(WithLogger(logger))),
+	)
+	srv.RegisterService(&testServiceDesc, svc)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestUnary(t *testing.T) {
+	var logged []string
+	logger := func(_ context.Context, fullMethod string, err error) {
+		logged = append(logged, fmt.Sprintf("%s: %+v", fullMethod, err))
+	}
+
+	cause := errors.New("select * from users: dsn user:secret@db")
+	svc := &testService{err: errors.WithMessage(errors.WrapCode(cause, errUserNotFound, "uid 42"), "get user")}
+	conn := dial(t, svc, logger)
+
+	err := conn.Invoke(context.Background(), "/grpcerrors.Test/Unary", new(emptypb.Empty), new(emptypb.Empty))
+	assert.True(t, errors.IsCode(err, errUserNotFound))
+	assert.True(t, errors.IsRemote(err))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "User not found", err.Error())
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "secret")
+
+	assert.Len(t, logged, 1)
+	assert.True(t, strings.HasPrefix(logged[0], "/grpcerrors.Test/Unary: "))
+	assert.Contains(t, logged[0], "secret")
+}
+
+func TestUnaryLocalized(t *testing.T) {
+	svc := &testService{err: errors.NewWithCode(errUserNotFound, "uid 42")}
+	conn := dial(t, svc, nil)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "zh-CN")
+	err := conn.Invoke(ctx, "/grpcerrors.Test/Unary", new(emptypb.Empty), new(emptypb.Empty))
+	assert.True(t, errors.IsCode(err, errUserNotFound))
+	assert.Equal(t, "用户不存在", err.Error())
+}
+
+func TestUnaryStatusError(t *testing.T) {
+	svc := &testService{err: status.Error(codes.PermissionDenied, "denied")}
+	conn := dial(t, svc, nil)
+
+	err := conn.Invoke(context.Background(), "/grpcerrors.Test/Unary", new(emptypb.Empty), new(emptypb.Empty))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, http.StatusForbidden, errors.ParseCoder(err).HTTPStatus())
+}
+
+func TestUnaryForwarded(t *testing.T) {
+	// an error of an upstream service, whose code is unknown here
+	upstream, _ := status.New(codes.FailedPrecondition, "Order closed").
+		WithDetails(&errors.Status{Code: 5000, Http: http.StatusBadRequest, Metadata: map[string]string{"order": "o1"}})
+
+	for _, forwarded := range []error{
+		upstream.Err(),
+		errors.WithMessage(upstream.Err(), "close order"),
+		errors.FromGRPC(upstream.Err()),
+	} {
+		conn := dial(t, &testService{err: forwarded}, nil)
+
+		err := conn.Invoke(context.Background(), "/grpcerrors.Test/Unary", new(emptypb.Empty), new(emptypb.Empty))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.True(t, errors.IsCode(err, 5000))
+		assert.Equal(t, http.StatusBadRequest, errors.ParseCoder(err).HTTPStatus())
+		assert.Equal(t, "Order closed", err.Error())
+		assert.Equal(t, map[string]interface{}{"order": "o1"}, errors.Fields(err))
+	}
+}
+
+func TestStream(t *testing.T) {
+	svc := &testService{err: errors.NewWithCode(errUserNotFound, "uid 42")}
+	conn := dial(t, svc, nil)
+
+	stream, err := conn.NewStream(context.Background(), &testServiceDesc.Streams[0], "/grpcerrors.Test/Stream")
+	assert.NoError(t, err)
+	assert.NoError(t, stream.SendMsg(new(emptypb.Empty)))
+	assert.NoError(t, stream.CloseSend())
+	assert.NoError(t, stream.RecvMsg(new(emptypb.Empty)))
+
+	err = stream.RecvMsg(new(emptypb.Empty))
+	assert.True(t, errors.IsCode(err, errUserNotFound))
+	assert.True(t, errors.IsRemote(err))
+
+	svc.err = nil
+	stream, err = conn.NewStream(context.Background(), &testServiceDesc.Streams[0], "/grpcerrors.Test/Stream")
+	assert.NoError(t, err)
+	assert.NoError(t, stream.CloseSend())
+	assert.NoError(t, stream.RecvMsg(new(emptypb.Empty)))
+	assert.Equal(t, io.EOF, stream.RecvMsg(new(emptypb.Empty)))
+}
@@ -0,0 +1,241 @@
+package errors
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeText = "text/plain"
+)
+
+// HTTPError is the JSON envelope rendered by WriteHTTP.
+type HTTPError struct {
+	// Code is the business code of the error.
+	Code int `json:"code"`
+
+	// Message is the external (user) facing error text.
+	Message string `json:"message"`
+
+	// Reference is the detail document of the code, if any.
+	Reference string `json:"reference,omitempty"`
+
+	// Metadata holds the fields attached to the error by WithFields.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Detail is the internal error message, only set in debug mode.
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewHTTPError returns the JSON envelope of err.
+// The message is the Public one, the Internal one is added as detail in debug mode.
+func NewHTTPError(err error) *HTTPError {
+	coder := ParseCoder(err)
+
+	herr := &HTTPError{
+		Code:      coder.Code(),
+		Message:   publicMessage(coder),
+		Reference: coder.Reference(),
+		Metadata:  metadata(err),
+	}
+	if debug {
+		herr.Detail = Internal(err)
+	}
+
+	return herr
+}
+
+// WriteHTTP writes err to w with the HTTP status of its Coder.
+// The body is negotiated with the Accept header of r: the JSON envelope
+// HTTPError by default, the Problem details, or the external message as plain text.
+// The external message is translated with the Accept-Language header of r.
+// Only the Public message is written, unless in debug mode.
+// r may be nil.
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	var accept, acceptLanguage string
+	if r != nil {
+		accept = r.Header.Get("Accept")
+		acceptLanguage = r.Header.Get("Accept-Language")
+	}
+
+	coder := ParseCoder(err)
+	status := coder.HTTPStatus()
+
+	herr := NewHTTPError(err)
+	message, lang := localize(coder, registryOf(err, DefaultRegistry), acceptLanguage)
+	herr.Message = message
+
+	header := w.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	if lang != "" {
+		header.Set("Content-Language", lang)
+	}
+
+	switch negotiate(accept, contentTypeJSON, contentTypeProblem, contentTypeText) {
+	case contentTypeProblem:
+		p := NewProblem(err, debug)
+		p.Title = message
+
+		header.Set("Content-Type", contentTypeProblem)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(p)
+	case contentTypeText:
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, herr.Message)
+		if herr.Detail != "" {
+			_, _ = io.WriteString(w, "\n\n"+herr.Detail)
+		}
+	default:
+		header.Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(herr)
+	}
+}
+
+// negotiate returns the media type of offers preferred by the Accept header.
+// The first offer is the default.
+func negotiate(accept string, offers ...string) string {
+	best, bestQ := offers[0], 0.0
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		for _, offer := range offers {
+			if q > bestQ && matchMediaType(mediaType, offer) {
+				best, bestQ = offer, q
+			}
+		}
+	}
+
+	return best
+}
+
+// matchMediaType reports whether the media range of an Accept header matches offer.
+func matchMediaType(mediaRange, offer string) bool {
+	if mediaRange == "*/*" || mediaRange == offer {
+		return true
+	}
+
+	prefix := strings.TrimSuffix(mediaRange, "*")
+
+	return prefix != mediaRange && strings.HasPrefix(offer, prefix)
+}
+
+// HandlerFunc is an http handler which returns an error.
+// The error is rendered by HTTPMiddleware if next to it, otherwise by WriteHTTP.
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// ServeHTTP calls f(w, r) and renders the returned error, unless the handler has
+// already written the response header.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if slot, ok := r.Context().Value(httpErrorKey{}).(*httpErrorSlot); ok {
+		slot.err = f(w, r)
+		return
+	}
+
+	rw := &responseWriter{ResponseWriter: w}
+	if err := f(rw, r); err != nil && !rw.wroteHeader {
+		WriteHTTP(w, r, err)
+	}
+}
+
+type httpErrorKey struct{}
+
+type httpErrorSlot struct {
+	err error
+}
+
+// HTTPMiddleware renders the errors returned by the HandlerFunc handlers of next
+// with WriteHTTP, unless the handler has already written the response header.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slot := new(httpErrorSlot)
+		rw := &responseWriter{ResponseWriter: w}
+
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), httpErrorKey{}, slot)))
+
+		if slot.err != nil && !rw.wroteHeader {
+			WriteHTTP(w, r, slot.err)
+		}
+	})
+}
+
+// responseWriter records whether the response header was written.
+// It passes http.Flusher, http.Hijacker and io.ReaderFrom through to the original
+// http.ResponseWriter: Flush is a no-op and Hijack fails if it does not implement them.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the original http.ResponseWriter, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush sends the buffered data to the client, see http.Flusher.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection, see http.Hijacker.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+
+	return conn, rw, err
+}
+
+// ReadFrom copies src to the response, see io.ReaderFrom.
+func (w *responseWriter) ReadFrom(src io.Reader) (int64, error) {
+	w.wroteHeader = true
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(src)
+	}
+
+	return io.Copy(struct{ io.Writer }{w.ResponseWriter}, src)
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTTP(t *testing.T) {
+	err := WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42)
+
+	tests := []struct {
+		name            string
+		accept          string
+		wantContentType string
+		wantBody        string
+	}{
+		{"default", "", "application/json; charset=utf-8", `{"code":1004,"message":"user no register, go to create","metadata":{"uid":"42"}}` + "\n"},
+		{"json", "application/json", "application/json; charset=utf-8", `{"code":1004,"message":"user no register, go to create","metadata":{"uid":"42"}}` + "\n"},
+		{"any", "*/*", "application/json; charset=utf-8", `{"code":1004,"message":"user no register, go to create","metadata":{"uid":"42"}}` + "\n"},
+		{"text", "text/plain", "text/plain; charset=utf-8", "user no register, go to create"},
+		{"quality", "application/json;q=0.5, text/*", "text/plain; charset=utf-8", "user no register, go to create"},
+		{"unsupported", "image/png", "application/json; charset=utf-8", `{"code":1004,"message":"user no register, go to create","metadata":{"uid":"42"}}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/user", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			WriteHTTP(w, r, err)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/user", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New("database conn failed!")
+	}))
+	mux.Handle("/written", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusAccepted)
+		return NewWithCode(ErrEOF, "eof")
+	}))
+	handler := HTTPMiddleware(mux)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/user", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var herr HTTPError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &herr))
+	assert.Equal(t, HTTPError{
+		Code:      UnknownCoder.Code(),
+		Message:   UnknownCoder.String(),
+		Reference: UnknownCoder.Reference(),
+	}, herr)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/written", nil))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestHTTPMiddlewareWriter(t *testing.T) {
+	handler := HTTPMiddleware(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		_, _ = io.Copy(w, strings.NewReader("partial"))
+		w.(http.Flusher).Flush()
+
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.Equal(t, http.ErrNotSupported, err)
+
+		return New("stream broken")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "partial", w.Body.String())
+}
+
+func TestHandlerFuncWritten(t *testing.T) {
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		_, _ = w.Write([]byte("partial"))
+		return New("write failed")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+
+	w = httptest.NewRecorder()
+	HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewWithCode(ErrEOF, "eof")
+	}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/status"
+)
+
+// Localizer is implemented by Coders which carry translations of their
+// external message.
+type Localizer interface {
+	// Localize returns the external message translated into lang,
+	// and reports whether there is such a translation.
+	Localize(lang string) (string, bool)
+}
+
+// RegisterTranslations register the external messages of codes translated into lang
+// in DefaultRegistry.
+// It will override the exist translations of the same codes.
+func RegisterTranslations(lang string, messages map[int]string) {
+	DefaultRegistry.RegisterTranslations(lang, messages)
+}
+
+// Localize returns the external message of err's Coder translated into lang.
+// lang is a language tag, e.g. "zh-CN", or a list of them in the format of
+// the Accept-Language header. A tag falls back to its primary language, and
+// String() of the Coder is returned if there is no translation.
+func Localize(err error, lang string) string {
+	return DefaultRegistry.Localize(err, lang)
+}
+
+// LocalizedGRPCStatus is like GRPCStatus, but the message of the status is
+// translated into lang as Localize does.
+func LocalizedGRPCStatus(err error, lang string) *status.Status {
+	s := GRPCStatus(err)
+	if s == nil {
+		return nil
+	}
+
+	message, matched := localize(ParseCoder(err), registryOf(err, DefaultRegistry), lang)
+	if matched == "" {
+		return s
+	}
+
+	p := s.Proto()
+	p.Message = message
+
+	return status.FromProto(p)
+}
+
+// localize returns the external message of coder translated into lang with the
+// translations of r, and the matched language tag, if any.
+func localize(coder Coder, r *Registry, lang string) (string, string) {
+	for _, tag := range parseAcceptLanguage(lang) {
+		for _, l := range []string{tag, primaryLanguage(tag)} {
+			if message, ok := translate(coder, r, l); ok {
+				return message, l
+			}
+		}
+	}
+
+	return publicMessage(coder), ""
+}
+
+func translate(coder Coder, r *Registry, lang string) (string, bool) {
+	if l, ok := coder.(Localizer); ok {
+		if message, ok := l.Localize(lang); ok {
+			return message, true
+		}
+	}
+
+	return r.translation(lang, coder.Code())
+}
+
+// primaryLanguage returns the primary language subtag of tag, e.g. "zh" of "zh-cn".
+func primaryLanguage(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i > 0 {
+		return tag[:i]
+	}
+
+	return tag
+}
+
+// parseAcceptLanguage returns the lower-cased language tags of an
+// Accept-Language header ordered by quality. The wildcard is ignored.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q <= 0 {
+				continue
+			}
+		}
+
+		tags = append(tags, weighted{tag, q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	ret := make([]string, 0, len(tags))
+	for _, t := range tags {
+		ret = append(ret, t.tag)
+	}
+
+	return ret
+}
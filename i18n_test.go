@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type localizedCoder struct {
+	defaultCoder
+}
+
+func (c localizedCoder) Localize(lang string) (string, bool) {
+	if lang == "fr" {
+		return "Erreur de configuration", true
+	}
+
+	return "", false
+}
+
+func TestLocalize(t *testing.T) {
+	RegisterTranslations("zh", map[int]string{ErrUserNoRegister: "用户未注册，请先创建"})
+	RegisterTranslations("zh-TW", map[int]string{ErrUserNoRegister: "用戶未註冊，請先創建"})
+
+	err := NewWithCode(ErrUserNoRegister, "uid %d", 42)
+
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"", "user no register, go to create"},
+		{"en", "user no register, go to create"},
+		{"zh", "用户未注册，请先创建"},
+		{"zh-CN", "用户未注册，请先创建"},
+		{"ZH-tw", "用戶未註冊，請先創建"},
+		{"en-US, zh;q=0.8", "用户未注册，请先创建"},
+		{"zh;q=0.5, zh-TW", "用戶未註冊，請先創建"},
+		{"*, zh;q=0", "user no register, go to create"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Localize(err, tt.lang), tt.lang)
+	}
+
+	coder := localizedCoder{defaultCoder{2001, http.StatusInternalServerError, "Configuration error", ""}}
+	Register(coder)
+	assert.Equal(t, "Erreur de configuration", Localize(NewWithCode(coder.Code(), "config"), "fr-CA"))
+}
+
+func TestLocalizedGRPCStatus(t *testing.T) {
+	RegisterTranslations("zh", map[int]string{ErrUserNoRegister: "用户未注册，请先创建"})
+
+	err := NewWithCode(ErrUserNoRegister, "uid %d", 42)
+	s := LocalizedGRPCStatus(err, "zh-CN")
+	assert.Equal(t, "用户未注册，请先创建", s.Message())
+	assert.True(t, IsCode(s.Err(), ErrUserNoRegister))
+	assert.Equal(t, "user no register, go to create", LocalizedGRPCStatus(err, "en").Message())
+}
+
+func TestWriteHTTPLocalized(t *testing.T) {
+	RegisterTranslations("zh", map[int]string{ErrUserNoRegister: "用户未注册，请先创建"})
+
+	r := httptest.NewRequest(http.MethodGet, "/user", nil)
+	r.Header.Set("Accept", "text/plain")
+	r.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+	w := httptest.NewRecorder()
+
+	WriteHTTP(w, r, NewWithCode(ErrUserNoRegister, "uid %d", 42))
+	assert.Equal(t, "用户未注册，请先创建", w.Body.String())
+	assert.Equal(t, "zh", w.Header().Get("Content-Language"))
+}
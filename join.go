@@ -0,0 +1,184 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc/status"
+)
+
+// CoderSelector picks the Coder which represents an aggregate error from the
+// Coders of its coded children. coders is never empty.
+type CoderSelector func(coders []Coder) Coder
+
+// FirstCoder selects the Coder of the first coded child.
+func FirstCoder(coders []Coder) Coder {
+	return coders[0]
+}
+
+// HighestHTTPStatusCoder selects the Coder with the highest HTTP status,
+// so a server failure wins over a client failure. Ties keep the earliest child.
+func HighestHTTPStatusCoder(coders []Coder) Coder {
+	c := coders[0]
+	for _, coder := range coders[1:] {
+		if coder.HTTPStatus() > c.HTTPStatus() {
+			c = coder
+		}
+	}
+
+	return c
+}
+
+// PriorityCoder returns a CoderSelector which selects the Coder whose code
+// appears first in codes. If no child carries one of codes, the first coded
+// child wins.
+func PriorityCoder(codes ...int) CoderSelector {
+	return func(coders []Coder) Coder {
+		for _, code := range codes {
+			for _, coder := range coders {
+				if coder.Code() == code {
+					return coder
+				}
+			}
+		}
+
+		return coders[0]
+	}
+}
+
+// DefaultCoderSelector is used by aggregate errors created without an
+// explicit CoderSelector.
+var DefaultCoderSelector CoderSelector = FirstCoder
+
+// Join returns an error that wraps the given errors.
+// Any nil error values are discarded.
+// Join returns nil if every value in errs is nil.
+func Join(errs ...error) error {
+	return JoinWith(nil, errs...)
+}
+
+// JoinWith is like Join, but the Coder of the returned error is chosen by
+// selector. A nil selector means DefaultCoderSelector.
+func JoinWith(selector CoderSelector, errs ...error) error {
+	e := &joinError{selector: selector}
+	for _, err := range errs {
+		if err != nil {
+			e.errs = append(e.errs, err)
+		}
+	}
+
+	if len(e.errs) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Append appends errs to err. If err is an aggregate error created by Join,
+// the errors are added to a copy of it, otherwise a new aggregate holding err
+// and errs is returned. Any nil error values are discarded.
+func Append(err error, errs ...error) error {
+	if e, ok := err.(*joinError); ok {
+		all := make([]error, 0, len(e.errs)+len(errs))
+		all = append(all, e.errs...)
+		all = append(all, errs...)
+
+		return JoinWith(e.selector, all...)
+	}
+
+	return Join(append([]error{err}, errs...)...)
+}
+
+// Errors returns the errors wrapped by err if it is an aggregate error,
+// a single element slice holding err if it is not, and nil if err is nil.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(interface{ Unwrap() []error }); ok {
+		return e.Unwrap()
+	}
+
+	return []error{err}
+}
+
+type joinError struct {
+	errs     []error
+	selector CoderSelector
+}
+
+// Error returns the messages of all wrapped errors separated by "; ".
+func (e *joinError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap provides compatibility for Go 1.20 multi-error chains.
+func (e *joinError) Unwrap() []error { return e.errs }
+
+// impl grpc func GRPCStatus() *Status
+func (e *joinError) GRPCStatus() *status.Status { return GRPCStatus(e) }
+
+// selectCoder returns the Coder chosen by selector among the coded errors
+// of errs, if any, whose codes are looked up in r. A nil selector means DefaultCoderSelector.
+func selectCoder(errs []error, selector CoderSelector, r *Registry) (Coder, bool) {
+	var coders []Coder
+	for _, err := range errs {
+		if coder, ok := lookupCoder(err, r); ok {
+			coders = append(coders, coder)
+		}
+	}
+
+	if len(coders) == 0 {
+		return nil, false
+	}
+
+	if selector == nil {
+		selector = DefaultCoderSelector
+	}
+
+	return selector(coders), true
+}
+
+// Format formats the aggregate error the same way withCode does for the
+// '#', '-' and '+' flags of the 'v' verb. Otherwise, it writes Error().
+func (e *joinError) Format(state fmt.State, verb rune) {
+	e.formatChain(state, verb, e)
+}
+
+func (e *joinError) formatChain(state fmt.State, verb rune, root error) {
+	switch verb {
+	case 'v':
+		if state.Flag('+') {
+			errs := list(e)
+			formatChain(state, root, errs, len(errs))
+
+			return
+		}
+
+		if state.Flag('#') || state.Flag('-') {
+			// only the head of every child, like withCode does for its chain
+			errs := make([]error, 0, len(e.errs))
+			for _, err := range e.errs {
+				if l := list(err); len(l) > 0 {
+					errs = append(errs, l[0])
+				}
+			}
+			formatChain(state, root, errs, len(errs))
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(state, e.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	}
+}
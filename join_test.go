@@ -0,0 +1,81 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/status"
+)
+
+func TestJoin(t *testing.T) {
+	assert.Nil(t, Join())
+	assert.Nil(t, Join(nil, nil))
+
+	std := stderrors.New("std error")
+	err := Join(nil, NewWithCode(ErrInvalidJSON, "json error"), std)
+	assert.Equal(t, "Data is not valid JSON; std error", err.Error())
+	assert.Len(t, Errors(err), 2)
+	assert.True(t, Is(err, std))
+
+	err = Append(err, NewWithCode(ErrEOF, "eof"))
+	assert.Len(t, Errors(err), 3)
+	assert.Equal(t, []error{std}, Errors(std))
+}
+
+func TestJoinCoder(t *testing.T) {
+	badRequest := NewWithCode(ErrUserNoRegister, "uid 1")
+	internal := NewWithCode(ErrEOF, "eof")
+	std := stderrors.New("std error")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"first", Join(std, badRequest, internal), ErrUserNoRegister},
+		{"highest http status", JoinWith(HighestHTTPStatusCoder, std, badRequest, internal), ErrEOF},
+		{"priority", JoinWith(PriorityCoder(ErrLoadConfigFailed, ErrEOF), badRequest, internal), ErrEOF},
+		{"priority fallback", JoinWith(PriorityCoder(ErrLoadConfigFailed), badRequest, internal), ErrUserNoRegister},
+		{"uncoded", Join(std, stderrors.New("other")), UnknownCoder.Code()},
+		{"wrapped", WithMessage(Join(std, internal), "batch"), ErrEOF},
+		{"std multi error", fmt.Errorf("%w, %w", std, internal), ErrEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, ParseCoder(tt.err).Code())
+
+			s, ok := status.FromError(GRPCStatus(tt.err).Err())
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantCode, ParseCoder(s.Err()).Code())
+		})
+	}
+}
+
+func TestJoinIsCode(t *testing.T) {
+	err := Join(NewWithCode(ErrUserNoRegister, "uid 1"), NewWithCode(ErrEOF, "eof"))
+	assert.True(t, IsCode(err, ErrUserNoRegister))
+	assert.True(t, IsCode(err, ErrEOF))
+	assert.False(t, IsCode(err, ErrInvalidJSON))
+
+	// the code of a wrapping withCode hides the aggregate
+	err = WrapCode(err, ErrLoadConfigFailed, "batch failed")
+	assert.True(t, IsCode(err, ErrLoadConfigFailed))
+	assert.False(t, IsCode(err, ErrEOF))
+}
+
+func TestJoinFormat(t *testing.T) {
+	err := Join(NewWithCode(ErrUserNoRegister, "uid 1"), WithMessage(NewWithCode(ErrEOF, "eof"), "read"))
+
+	assert.Equal(t, "user no register, go to create; read", fmt.Sprintf("%s", err))
+	assert.Equal(t, `[{"error":"user no register, go to create"},{"error":"read"}]`, fmt.Sprintf("%#v", err))
+	assert.Equal(t, 2, strings.Count(fmt.Sprintf("%-v", err), "#"))
+	assert.Equal(t, 3, strings.Count(fmt.Sprintf("%+v", err), "#"))
+
+	wrapped := WrapCode(err, ErrLoadConfigFailed, "batch failed")
+	assert.Equal(t, "Load configuration file failed", fmt.Sprintf("%v", wrapped))
+	assert.Contains(t, fmt.Sprintf("%+v", wrapped), "(1002) End of input, eof")
+}
@@ -0,0 +1,318 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+)
+
+// kinds of the links of a JSON encoded error chain.
+const (
+	linkCode    = "code"
+	linkStack   = "stack"
+	linkMessage = "message"
+	linkFields  = "fields"
+	linkJoin    = "join"
+	linkError   = "error"
+)
+
+// jsonLink is the JSON encoding of an error of a chain, which nests its cause.
+type jsonLink struct {
+	// Kind is the kind of the error: code, stack, message, fields, join or error.
+	Kind string `json:"kind"`
+
+	// Code is the code of a withCode, or the one selected by an aggregate.
+	Code int `json:"code,omitempty"`
+
+	// HTTP is the HTTP status of the Coder of Code, if it was registered.
+	HTTP int `json:"http,omitempty"`
+
+	// Message is the external message of the Coder of Code.
+	Message string `json:"message,omitempty"`
+
+	// Reference is the reference document of the Coder of Code.
+	Reference string `json:"reference,omitempty"`
+
+	// GRPC is the explicit gRPC code of the Coder of Code, if it is a GRPCCoder.
+	GRPC string `json:"grpc,omitempty"`
+
+	// Domain is the domain of Code, empty for the default one.
+	Domain string `json:"domain,omitempty"`
+
+	// Error is the internal message of the error.
+	Error string `json:"error,omitempty"`
+
+	// Redacted is the redacted internal message of the error, if it has one.
+	Redacted string `json:"redacted,omitempty"`
+
+	// Metadata holds the fields attached by WithFields.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Frames is the symbolized stack, innermost first.
+	Frames []jsonFrame `json:"frames,omitempty"`
+
+	// Cause is the wrapped error, if any.
+	Cause *jsonLink `json:"cause,omitempty"`
+
+	// Errors are the errors of an aggregate.
+	Errors []*jsonLink `json:"errors,omitempty"`
+}
+
+// jsonFrame is the JSON encoding of a symbolized frame.
+type jsonFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// MarshalJSON encodes the whole chain of err, see UnmarshalJSON.
+// The encoding carries both the internal messages and their redacted ones, it is
+// not a log sink, see SetLogRedacted.
+func MarshalJSON(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(newJSONLink(err))
+}
+
+// UnmarshalJSON rebuilds an error chain encoded by MarshalJSON, or by the MarshalJSON
+// method of the errors of this package. The codes, the external and internal messages,
+// the fields and the stacks of the chain are restored, so ParseCoder, IsCode and
+// the '%+v' format behave like for the original error. Errors of other packages are
+// restored by their message and cause only.
+func UnmarshalJSON(data []byte) (error, error) {
+	var link *jsonLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return nil, err
+	}
+
+	return link.rebuild(), nil
+}
+
+// MarshalJSON encodes the whole chain of w.
+func (w *withCode) MarshalJSON() ([]byte, error) { return MarshalJSON(w) }
+
+// MarshalJSON encodes the whole chain of w.
+func (w *withStack) MarshalJSON() ([]byte, error) { return MarshalJSON(w) }
+
+// MarshalJSON encodes the whole chain of w.
+func (w *withMessage) MarshalJSON() ([]byte, error) { return MarshalJSON(w) }
+
+// MarshalJSON encodes the whole chain of w.
+func (w *withFields) MarshalJSON() ([]byte, error) { return MarshalJSON(w) }
+
+// MarshalJSON encodes the whole chain of e.
+func (e *joinError) MarshalJSON() ([]byte, error) { return MarshalJSON(e) }
+
+// newJSONLink returns the JSON encoding of err and its causes.
+func newJSONLink(err error) *jsonLink {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *withCode:
+		link := &jsonLink{
+			Kind:     linkCode,
+			Code:     e.code,
+			Error:    e.err.Error(),
+			Redacted: redactedOf(e.err),
+			Cause:    newJSONLink(e.cause),
+		}
+		if coder, ok := e.lookup(DefaultRegistry); ok {
+			link.setCoder(coder)
+		}
+		if e.registry != nil {
+			link.Domain = e.registry.Namespace()
+		}
+		if e.stack.size() > e.skipDepth {
+			link.Frames = newJSONFrames(e.stack.slice(e.skipDepth, e.stack.size()))
+		}
+
+		return link
+	case *withStack:
+		return &jsonLink{Kind: linkStack, Frames: newJSONFrames(e.stack), Cause: newJSONLink(e.Cause())}
+	case *withMessage:
+		return &jsonLink{Kind: linkMessage, Error: e.msg, Redacted: e.redacted, Cause: newJSONLink(e.cause)}
+	case *withFields:
+		md := make(map[string]string, len(e.fields))
+		for k, v := range e.fields {
+			md[k] = fmt.Sprint(v)
+		}
+
+		return &jsonLink{Kind: linkFields, Metadata: md, Cause: newJSONLink(e.cause)}
+	case *joinError:
+		link := &jsonLink{Kind: linkJoin}
+		for _, err := range e.errs {
+			link.Errors = append(link.Errors, newJSONLink(err))
+		}
+		if coder, ok := selectCoder(e.errs, e.selector, DefaultRegistry); ok {
+			link.Code = coder.Code()
+		}
+
+		return link
+	case interface{ Unwrap() []error }:
+		link := &jsonLink{Kind: linkError, Error: err.Error()}
+		for _, err := range e.Unwrap() {
+			link.Errors = append(link.Errors, newJSONLink(err))
+		}
+
+		return link
+	default:
+		return &jsonLink{Kind: linkError, Error: err.Error(), Redacted: redactedOf(err), Cause: newJSONLink(Unwrap(err))}
+	}
+}
+
+// redactedOf returns the redacted message of err, if it has one.
+func redactedOf(err error) string {
+	switch e := err.(type) {
+	case *redactable:
+		return e.redacted
+	case *redactableJoin:
+		return e.redacted
+	}
+
+	return ""
+}
+
+// setCoder records coder in link.
+func (link *jsonLink) setCoder(coder Coder) {
+	link.Code = coder.Code()
+	link.HTTP = coder.HTTPStatus()
+	link.Message = coder.String()
+	link.Reference = coder.Reference()
+	if c, ok := coder.(GRPCCoder); ok {
+		link.GRPC = c.GRPCCode().String()
+	}
+}
+
+// rebuild returns the error encoded by link.
+func (link *jsonLink) rebuild() error {
+	if link == nil {
+		return nil
+	}
+
+	cause := link.Cause.rebuild()
+
+	switch link.Kind {
+	case linkCode:
+		w := &withCode{
+			err:   link.message(),
+			code:  link.Code,
+			cause: cause,
+			stack: newStackOf(link.Frames),
+		}
+		if link.HTTP != 0 {
+			w.coder = defaultCoder{link.Code, link.HTTP, link.Message, link.Reference}
+			if code, ok := grpcCodes[link.GRPC]; ok {
+				w.coder = WithGRPCCode(w.coder, code)
+			}
+		}
+		if link.Domain != "" {
+			w.registry = lookupRegistry(link.Domain)
+		}
+
+		return w
+	case linkStack:
+		return &withStack{cause, newStackOf(link.Frames)}
+	case linkMessage:
+		return &withMessage{cause: cause, msg: link.Error, redacted: link.Redacted}
+	case linkFields:
+		fields := make(map[string]interface{}, len(link.Metadata))
+		for k, v := range link.Metadata {
+			fields[k] = v
+		}
+
+		return &withFields{cause: cause, fields: fields}
+	case linkJoin:
+		e := &joinError{errs: link.rebuildErrors()}
+		if link.Code != 0 {
+			e.selector = PriorityCoder(link.Code)
+		}
+
+		return e
+	default:
+		if len(link.Errors) > 0 {
+			return &jsonMultiError{msg: link.Error, errs: link.rebuildErrors()}
+		}
+
+		if cause == nil {
+			return link.message()
+		}
+
+		return &jsonError{msg: link.Error, cause: cause, redacted: link.Redacted}
+	}
+}
+
+// message returns the message of link as an error, redactable if it has a redacted one.
+func (link *jsonLink) message() error {
+	if link.Redacted != "" {
+		return &redactable{error: stderrors.New(link.Error), redacted: link.Redacted}
+	}
+
+	return stderrors.New(link.Error)
+}
+
+func (link *jsonLink) rebuildErrors() []error {
+	errs := make([]error, 0, len(link.Errors))
+	for _, l := range link.Errors {
+		if err := l.rebuild(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// newJSONFrames returns the symbolized frames of st, innermost first.
+func newJSONFrames(st *stack) []jsonFrame {
+	syms := st.symbols()
+	if len(syms) == 0 {
+		return nil
+	}
+
+	frames := make([]jsonFrame, 0, len(syms))
+	for _, sym := range syms {
+		frames = append(frames, jsonFrame{sym.function, sym.file, sym.line})
+	}
+
+	return frames
+}
+
+// newStackOf returns the decoded stack of frames.
+func newStackOf(frames []jsonFrame) *stack {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	syms := make([]symbol, 0, len(frames))
+	for _, f := range frames {
+		syms = append(syms, symbol{f.Function, f.File, f.Line})
+	}
+
+	return &stack{decoded: syms}
+}
+
+// jsonError is a decoded error of another package.
+type jsonError struct {
+	msg      string
+	cause    error
+	redacted string
+}
+
+func (e *jsonError) Error() string { return e.msg }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (e *jsonError) Unwrap() error { return e.cause }
+
+// jsonMultiError is a decoded aggregate error of another package.
+type jsonMultiError struct {
+	msg  string
+	errs []error
+}
+
+func (e *jsonMultiError) Error() string { return e.msg }
+
+// Unwrap provides compatibility for Go 1.20 multi-error chains.
+func (e *jsonMultiError) Unwrap() []error { return e.errs }
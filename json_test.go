@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	inner := NewWithStack("connection refused: 100%%")
+	err := WithFields(WrapCode(WithMessage(inner, "select user"), ErrInvalidJSON, "load user"), "uid", 1)
+
+	data, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+
+	got, uerr := UnmarshalJSON(data)
+	assert.NoError(t, uerr)
+
+	assert.Equal(t, err.Error(), got.Error())
+	assert.True(t, IsCode(got, ErrInvalidJSON))
+	assert.Equal(t, ParseCoder(err), ParseCoder(got))
+	assert.Equal(t, Fields(err)["uid"], 1)
+	assert.Equal(t, "1", Fields(got)["uid"])
+	assert.Equal(t, fmt.Sprintf("%+v", err), fmt.Sprintf("%+v", got))
+	assert.Equal(t, fmt.Sprintf("%-v", err), fmt.Sprintf("%-v", got))
+	assert.Equal(t, fmt.Sprintf("%+v", Cause(err)), fmt.Sprintf("%+v", Cause(got)))
+
+	// a decoded chain encodes the same
+	again, jerr := json.Marshal(got)
+	assert.NoError(t, jerr)
+	assert.JSONEq(t, string(data), string(again))
+}
+
+func TestJSONDecodedStack(t *testing.T) {
+	data := []byte(`{"kind":"stack","frames":[{"function":"main.main","file":"/src/app/main.go","line":12}],` +
+		`"cause":{"kind":"error","error":"remote"}}`)
+
+	got, err := UnmarshalJSON(data)
+	assert.NoError(t, err)
+
+	// the decoded frames are rendered, but are not program counters of the process
+	assert.Equal(t, "remote\nmain.main\n\t/src/app/main.go:12", fmt.Sprintf("%+v", got))
+	assert.Nil(t, got.(StackTracer).StackTrace())
+	assert.Nil(t, MergedStackTrace(got))
+}
+
+func TestJSONUnregisteredCoder(t *testing.T) {
+	r := NewRegistry("")
+	r.Register(defaultCoder{9001, 409, "Conflict", "http://example.com"})
+	data, _ := MarshalJSON(NewWithCodeX(9001, "conflict", WithRegistry(r)))
+
+	// the Coder travels with the error
+	got, err := UnmarshalJSON(data)
+	assert.NoError(t, err)
+	assert.True(t, IsCode(got, 9001))
+	assert.Equal(t, 409, ParseCoder(got).HTTPStatus())
+	assert.Equal(t, "Conflict", got.Error())
+}
+
+func TestJSONJoin(t *testing.T) {
+	err := JoinWith(HighestHTTPStatusCoder,
+		NewWithCode(ConfigurationNotValid, "bad config"),
+		New("plain"),
+		NewWithCode(ErrEOF, "eof"),
+	)
+
+	data, jerr := MarshalJSON(err)
+	assert.NoError(t, jerr)
+
+	got, uerr := UnmarshalJSON(data)
+	assert.NoError(t, uerr)
+	assert.Equal(t, ParseCoder(err).Code(), ParseCoder(got).Code())
+	assert.True(t, IsCode(got, ConfigurationNotValid))
+	assert.True(t, IsCode(got, ErrEOF))
+	assert.Len(t, Errors(got), 3)
+	assert.Equal(t, err.Error(), got.Error())
+}
+
+func TestJSONNil(t *testing.T) {
+	data, err := MarshalJSON(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	got, err := UnmarshalJSON(data)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = UnmarshalJSON([]byte("{"))
+	assert.Error(t, err)
+}
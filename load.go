@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+)
+
+// grpcCodes are the gRPC codes by name, e.g. "NotFound".
+var grpcCodes = func() map[string]codes.Code {
+	m := make(map[string]codes.Code, codes.Unauthenticated+1)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+
+	return m
+}()
+
+// LoadCoders reads the definitions of codes from r and registers them in DefaultRegistry,
+// see Registry.LoadCoders.
+func LoadCoders(r io.Reader, format CatalogFormat) error {
+	return DefaultRegistry.LoadCoders(r, format)
+}
+
+// LoadCoders reads the definitions of codes from r and registers them in reg. The
+// definitions are an array of CatalogEntry in the format written by WriteCatalog,
+// either CatalogJSON or CatalogYAML, e.g.
+//
+//	[
+//	  {"code": 100001, "http": 400, "message": "Invalid user name", "grpc": "InvalidArgument"},
+//	  {"code": 100002, "http": 404, "message": "User not found", "metadata": {"owner": "account"}}
+//	]
+//
+// The definitions are validated before anything is registered: it returns an error
+// reporting every invalid definition, rather than panicking, and registers none of
+// them. Otherwise all of them are registered at once, overriding the exist codes,
+// so it may be called again to reload the messages at runtime. The errors already
+// created render with the reloaded messages.
+// It only adds and overrides codes: the codes missing from the definitions stay
+// registered, e.g. the ones loaded from another file, so a code dropped from a
+// reloaded file is only unregistered by a restart.
+// The "namespace" of a definition must be the one of reg. In strict mode, see
+// SetStrictRanges, the code must be in a claimed range and "range" must be its owner,
+// otherwise "range" is ignored.
+func (reg *Registry) LoadCoders(r io.Reader, format CatalogFormat) error {
+	if format != CatalogJSON && format != CatalogYAML {
+		return fmt.Errorf("load coders: unsupported format %q", format)
+	}
+
+	var entries []CatalogEntry
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&entries); err != nil {
+		return fmt.Errorf("load coders: %w", err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	state := reg.load()
+	seen := make(map[int]int, len(entries))
+	coders := make([]Coder, 0, len(entries))
+	var problems []error
+	for i, e := range entries {
+		problem := func(format string, args ...interface{}) {
+			problems = append(problems, fmt.Errorf("load coders: [%d] code %d: %s", i, e.Code, fmt.Sprintf(format, args...)))
+		}
+
+		if e.Code == 0 {
+			problem("code 0 is reserved")
+		}
+		if j, ok := seen[e.Code]; ok {
+			problem("duplicates [%d]", j)
+		}
+		seen[e.Code] = i
+		if e.Namespace != reg.namespace {
+			problem("namespace %q is not %q", e.Namespace, reg.namespace)
+		}
+		// any status of the valid range, e.g. the non-standard ClientClosed
+		if e.HTTP < 100 || e.HTTP > 599 {
+			problem("http status %d is invalid", e.HTTP)
+		}
+		if e.Message == "" {
+			problem("message is empty")
+		}
+		grpc, ok := grpcCodes[e.GRPC]
+		if e.GRPC != "" && !ok {
+			problem("grpc code %q is invalid", e.GRPC)
+		}
+		if state.strict {
+			switch cr := state.rangeOf(e.Code); {
+			case cr == nil:
+				problem("out of the claimed ranges")
+			case cr.name != e.Range:
+				problem("range %q is not the owner %q", e.Range, cr.name)
+			}
+		}
+
+		coders = append(coders, &loadedCoder{
+			defaultCoder: defaultCoder{e.Code, e.HTTP, e.Message, e.Reference},
+			grpc:         grpc,
+			hasGRPC:      e.GRPC != "",
+			metadata:     e.Metadata,
+		})
+	}
+
+	if len(problems) > 0 {
+		return Join(problems...)
+	}
+
+	reg.storeCodes(coders...)
+
+	return nil
+}
+
+// loadedCoder is a Coder defined by LoadCoders.
+type loadedCoder struct {
+	defaultCoder
+
+	grpc     codes.Code
+	hasGRPC  bool
+	metadata map[string]string
+}
+
+// GRPCCode returns the gRPC code of the definition, or the one of the HTTP status.
+func (coder *loadedCoder) GRPCCode() codes.Code {
+	if coder.hasGRPC {
+		return coder.grpc
+	}
+
+	return ToGRPCCode(coder.HTTPStatus())
+}
+
+// Metadata returns the metadata of the definition of the code.
+func (coder *loadedCoder) Metadata() map[string]string {
+	return coder.metadata
+}
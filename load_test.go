@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCoders(t *testing.T) {
+	r := NewRegistry("")
+	err := r.LoadCoders(strings.NewReader(`[
+		{"code": 100001, "http": 400, "message": "Invalid user name", "grpc": "FailedPrecondition"},
+		{"code": 100002, "http": 404, "message": "User not found", "reference": "http://example.com", "metadata": {"owner": "account"}}
+	]`), CatalogJSON)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 400, r.GetCoder(100001).HTTPStatus())
+	assert.Equal(t, "User not found", r.GetCoder(100002).String())
+	assert.Equal(t, "http://example.com", r.GetCoder(100002).Reference())
+
+	entries := r.Catalog()
+	assert.Equal(t, "FailedPrecondition", entries[0].GRPC)
+	assert.Equal(t, map[string]string{"owner": "account"}, entries[1].Metadata)
+
+	// the exported catalog loads back
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCatalog(&buf, entries, CatalogYAML))
+	again := NewRegistry("")
+	assert.NoError(t, again.LoadCoders(&buf, CatalogYAML))
+	assert.Equal(t, entries, again.Catalog())
+}
+
+func TestLoadCodersReload(t *testing.T) {
+	r := NewRegistry("")
+	assert.NoError(t, r.LoadCoders(strings.NewReader(`[{"code": 100001, "http": 400, "message": "Invalid"}]`), CatalogJSON))
+
+	err := NewWithCodeX(100001, "invalid", WithRegistry(r))
+	assert.Equal(t, "Invalid", r.ParseCoder(err).String())
+
+	// the errors already created render with the reloaded messages
+	assert.NoError(t, r.LoadCoders(strings.NewReader(`[{"code": 100002, "http": 499, "message": "Client closed"}]`), CatalogJSON))
+	assert.NoError(t, r.LoadCoders(strings.NewReader(`[{"code": 100001, "http": 400, "message": "Invalid user"}]`), CatalogJSON))
+	assert.Equal(t, "Invalid user", r.ParseCoder(err).String())
+	assert.Equal(t, "Invalid user", Public(err))
+	assert.Equal(t, "Invalid user", fmt.Sprintf("%v", err))
+
+	// the codes missing from the reloaded definitions stay registered
+	assert.Equal(t, ClientClosed, r.GetCoder(100002).HTTPStatus())
+}
+
+func TestLoadCodersInvalid(t *testing.T) {
+	r := newTestRegistry(t, "test.load")
+	r.RegisterRange("base", 100000, 100099)
+	r.SetStrictRanges(true)
+
+	err := r.LoadCoders(strings.NewReader(`[
+		{"namespace": "test.load", "code": 100001, "http": 400, "message": "Valid", "range": "base"},
+		{"namespace": "other", "code": 0, "http": 999, "message": "", "grpc": "Nope"},
+		{"namespace": "test.load", "code": 100001, "http": 400, "message": "Again", "range": "base"},
+		{"namespace": "test.load", "code": 200001, "http": 400, "message": "Out"},
+		{"namespace": "test.load", "code": 100002, "http": 400, "message": "Other owner", "range": "account"}
+	]`), CatalogJSON)
+	assert.Equal(t, []string{
+		"load coders: [1] code 0: code 0 is reserved",
+		`load coders: [1] code 0: namespace "other" is not "test.load"`,
+		"load coders: [1] code 0: http status 999 is invalid",
+		"load coders: [1] code 0: message is empty",
+		`load coders: [1] code 0: grpc code "Nope" is invalid`,
+		"load coders: [1] code 0: out of the claimed ranges",
+		"load coders: [2] code 100001: duplicates [0]",
+		"load coders: [3] code 200001: out of the claimed ranges",
+		`load coders: [4] code 100002: range "account" is not the owner "base"`,
+	}, strings.Split(err.Error(), "; "))
+
+	// none of them is registered
+	assert.Empty(t, r.Codes())
+
+	assert.Error(t, r.LoadCoders(strings.NewReader(`[{"code": 100001, "status": 400}]`), CatalogJSON))
+	assert.Error(t, r.LoadCoders(strings.NewReader(`{`), CatalogJSON))
+	assert.Error(t, r.LoadCoders(strings.NewReader(`[]`), CatalogCSV))
+}
@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// panicCode is the code of the errors converted from panics. It is read while
+// recovering the panics of any goroutine, so it is set atomically.
+var panicCode atomic.Int64
+
+func init() {
+	panicCode.Store(int64(UnknownCoder.Code()))
+}
+
+// SetPanicCode set the code of the errors converted from panics, the code of UnknownCoder by default.
+func SetPanicCode(code int) {
+	panicCode.Store(int64(code))
+}
+
+// Recover converts a panic into a coded error stored in *errp. It must be deferred directly:
+//
+//	func handle() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+//
+// The error carries the panic value, see PanicValue, and the stack of the panic site.
+// If there is no panic, *errp is left untouched.
+func Recover(errp *error) {
+	if v := recover(); v != nil {
+		*errp = newPanic(v)
+	}
+}
+
+// Try calls fn and returns its error, or the coded error of its panic as Recover does.
+func Try(fn func() error) (err error) {
+	defer Recover(&err)
+
+	return fn()
+}
+
+// Go calls fn in a new goroutine. The returned channel receives the error of fn, or
+// the coded error of its panic as Recover does, and is closed then. It is buffered,
+// so the channel may be ignored.
+func Go(fn func() error) <-chan error {
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		errc <- Try(fn)
+	}()
+
+	return errc
+}
+
+// PanicValue returns the value of the panic err was converted from, if any.
+func PanicValue(err error) (interface{}, bool) {
+	var p *panicError
+	if As(err, &p) {
+		return p.value, true
+	}
+
+	return nil, false
+}
+
+// newPanic returns the coded error of the panic value v.
+// It must be called by the deferred function calling recover.
+func newPanic(v interface{}) error {
+	p := &panicError{value: v}
+
+	return &withCode{
+		err:   p,
+		code:  int(panicCode.Load()),
+		cause: p,
+		stack: panicStack(),
+	}
+}
+
+// panicStack records the stack of the panic site, from the frame which called panic,
+// or which caused a runtime error, outwards.
+func panicStack() *stack {
+	pcs := make([]uintptr, 2*defaultStackDepth)
+	n := runtime.Callers(3, pcs)
+	pcs = pcs[:n]
+
+	// skip the deferred calls up to runtime.gopanic, then the runtime frames raising
+	// runtime errors, e.g. runtime.panicmem and runtime.sigpanic.
+	site := 0
+	for i, pc := range pcs {
+		if symbolize(pc)[0].function == "runtime.gopanic" {
+			site = i + 1
+			break
+		}
+	}
+	for site < len(pcs) && strings.HasPrefix(symbolize(pcs[site])[0].function, "runtime.") {
+		site++
+	}
+
+	pcs = pcs[site:]
+	if len(pcs) > defaultStackDepth {
+		pcs = pcs[:defaultStackDepth]
+	}
+
+	return &stack{pcs: pcs}
+}
+
+// panicError carries a recovered panic value.
+type panicError struct {
+	value interface{}
+}
+
+func (p *panicError) Error() string { return fmt.Sprintf("panic: %v", p.value) }
+
+// Unwrap returns the panic value if it is an error, e.g. a runtime.Error.
+func (p *panicError) Unwrap() error {
+	err, _ := p.value.(error)
+
+	return err
+}
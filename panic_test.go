@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func panicking() {
+	panic("boom")
+}
+
+func TestRecover(t *testing.T) {
+	run := func() (err error) {
+		defer Recover(&err)
+
+		panicking()
+
+		return nil
+	}
+
+	err := run()
+	assert.Error(t, err)
+	assert.True(t, IsCode(err, UnknownCoder.Code()))
+	assert.Equal(t, UnknownCoder.Code(), ParseCoder(err).Code())
+
+	v, ok := PanicValue(err)
+	assert.True(t, ok)
+	assert.Equal(t, "boom", v)
+	assert.Contains(t, fmt.Sprintf("%-v", err), "panic: boom")
+
+	// the stack starts at the panic site
+	var st StackTracer
+	assert.True(t, As(err, &st))
+	frames := st.StackTrace().Frames()
+	assert.Equal(t, "github.com/go-leo/errors.panicking", frames[0].Function)
+	assert.Equal(t, "github.com/go-leo/errors.TestRecover.func1", frames[1].Function)
+
+	_, ok = PanicValue(New("no panic"))
+	assert.False(t, ok)
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	err := Try(func() error {
+		var m map[string]int
+		m["nil"] = 1
+
+		return nil
+	})
+
+	var re runtime.Error
+	assert.True(t, As(err, &re))
+
+	var st StackTracer
+	assert.True(t, As(err, &st))
+	assert.Equal(t, "github.com/go-leo/errors.TestRecoverRuntimeError.func1", st.StackTrace().Frames()[0].Function)
+}
+
+func TestTry(t *testing.T) {
+	assert.NoError(t, Try(func() error { return nil }))
+
+	want := New("returned")
+	assert.Equal(t, want, Try(func() error { return want }))
+
+	SetPanicCode(ErrEOF)
+	defer SetPanicCode(UnknownCoder.Code())
+
+	err := Try(func() error { panic(want) })
+	assert.True(t, IsCode(err, ErrEOF))
+	assert.True(t, Is(err, want))
+}
+
+func TestGo(t *testing.T) {
+	assert.NoError(t, <-Go(func() error { return nil }))
+
+	errc := Go(func() error { panic("worker") })
+	err := <-errc
+	v, ok := PanicValue(err)
+	assert.True(t, ok)
+	assert.Equal(t, "worker", v)
+
+	_, open := <-errc
+	assert.False(t, open)
+}
+
+func TestPanicCodeConcurrent(t *testing.T) {
+	defer SetPanicCode(UnknownCoder.Code())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetPanicCode(ErrEOF)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, ok := PanicValue(<-Go(func() error { panic("worker") }))
+		assert.True(t, ok)
+	}
+	<-done
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	contentTypeProblem = "application/problem+json"
+
+	// problemTypeBlank is the problem type of problems without Reference.
+	problemTypeBlank = "about:blank"
+)
+
+// Problem is the RFC 9457 problem details (application/problem+json)
+// representation of a coded error. The business code and the metadata are
+// carried by extension members.
+type Problem struct {
+	// Type is the Reference of the Coder, "about:blank" if none.
+	Type string `json:"type"`
+
+	// Title is the external (user) facing error text.
+	Title string `json:"title"`
+
+	// Status is the HTTP status of the Coder.
+	Status int `json:"status"`
+
+	// Detail is the internal error message, only set when allowed.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance identifies the occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+
+	// Code is the business code of the error.
+	Code int `json:"code,omitempty"`
+
+	// Metadata holds the fields attached to the error by WithFields.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewProblem returns the problem details of err.
+// The internal error message is exposed as detail only if detail is true.
+func NewProblem(err error, detail bool) *Problem {
+	coder := ParseCoder(err)
+
+	p := &Problem{
+		Type:     coder.Reference(),
+		Title:    publicMessage(coder),
+		Status:   coder.HTTPStatus(),
+		Code:     coder.Code(),
+		Metadata: metadata(err),
+	}
+	if p.Type == "" {
+		p.Type = problemTypeBlank
+	}
+
+	if detail {
+		p.Detail = Internal(err)
+	}
+
+	return p
+}
+
+// ParseProblem parses an application/problem+json document.
+func ParseProblem(data []byte) (*Problem, error) {
+	p := new(Problem)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, WithStack(err)
+	}
+
+	return p, nil
+}
+
+// Err converts the problem back into a coded error received from a remote peer.
+// A problem without code is converted into an error of UnknownCoder's code.
+func (p *Problem) Err() error {
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	ref := p.Type
+	if ref == problemTypeBlank {
+		ref = ""
+	}
+
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+
+	code := p.Code
+	if code == 0 {
+		code = UnknownCoder.Code()
+	}
+
+	return newRemote(nil, defaultCoder{code, status, p.Title, ref}, message, p.Metadata)
+}
+
+// internalMessage returns the internal (developer) message of the head of err's chain.
+func internalMessage(err error) string {
+	errs := list(err)
+	if len(errs) == 0 {
+		return ""
+	}
+
+	return buildFormatInfo(errs[0], false).err
+}
+
+// redactedMessage returns the redacted internal message of the head of err's chain.
+func redactedMessage(err error) string {
+	errs := list(err)
+	if len(errs) == 0 {
+		return ""
+	}
+
+	return redactedText(errs[0])
+}
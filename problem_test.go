@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProblem(t *testing.T) {
+	err := WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42)
+
+	assert.Equal(t, &Problem{
+		Type:     "about:blank",
+		Title:    "user no register, go to create",
+		Status:   http.StatusBadRequest,
+		Code:     ErrUserNoRegister,
+		Metadata: map[string]string{"uid": "42"},
+	}, NewProblem(err, false))
+	assert.Equal(t, "uid 42", NewProblem(err, true).Detail)
+	assert.Equal(t, "http://github.com/go-leo/errors/README.md", NewProblem(New("std error"), false).Type)
+}
+
+func TestWriteHTTPProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/user", nil)
+	r.Header.Set("Accept", "application/problem+json, application/json;q=0.9")
+	w := httptest.NewRecorder()
+
+	WriteHTTP(w, r, NewWithCode(ErrUserNoRegister, "uid %d", 42))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"about:blank","title":"user no register, go to create","status":400,"code":1004}`, w.Body.String())
+}
+
+func TestParseProblem(t *testing.T) {
+	data, _ := json.Marshal(NewProblem(WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42), true))
+	p, err := ParseProblem(data)
+	assert.NoError(t, err)
+
+	e := p.Err()
+	assert.True(t, IsRemote(e))
+	assert.True(t, IsCode(e, ErrUserNoRegister))
+	assert.Equal(t, map[string]interface{}{"uid": "42"}, Fields(e))
+	assert.Equal(t, "uid 42", internalMessage(e))
+
+	// a problem of another api
+	p, err = ParseProblem([]byte(`{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403}`))
+	assert.NoError(t, err)
+
+	coder := ParseCoder(p.Err())
+	assert.Equal(t, UnknownCoder.Code(), coder.Code())
+	assert.Equal(t, http.StatusForbidden, coder.HTTPStatus())
+	assert.Equal(t, "You do not have enough credit.", coder.String())
+	assert.Equal(t, "https://example.com/probs/out-of-credit", coder.Reference())
+
+	_, err = ParseProblem([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package errors
+
+// kinds maps the kinds of the links to their protobuf enum.
+var kinds = map[string]Link_Kind{
+	linkCode:    Link_KIND_CODE,
+	linkStack:   Link_KIND_STACK,
+	linkMessage: Link_KIND_MESSAGE,
+	linkFields:  Link_KIND_FIELDS,
+	linkJoin:    Link_KIND_JOIN,
+	linkError:   Link_KIND_ERROR,
+}
+
+// ToProto encodes the whole chain of err into a Link message, see FromProto.
+// Like MarshalJSON, it carries both the internal messages and their redacted ones.
+// It returns nil if err is nil.
+func ToProto(err error) *Link {
+	return newJSONLink(err).toProto()
+}
+
+// FromProto rebuilds an error chain encoded by ToProto. Like UnmarshalJSON, the
+// codes, the messages, the fields and the stacks of the chain are restored.
+// It returns nil if msg is nil.
+func FromProto(msg *Link) error {
+	return newLinkFromProto(msg).rebuild()
+}
+
+func (link *jsonLink) toProto() *Link {
+	if link == nil {
+		return nil
+	}
+
+	msg := &Link{
+		Kind:     kinds[link.Kind],
+		Code:     int32(link.Code),
+		Http:     int32(link.HTTP),
+		Message:  link.Message,
+		Ref:      link.Reference,
+		Grpc:     link.GRPC,
+		Internal: link.Error,
+		Redacted: link.Redacted,
+		Metadata: link.Metadata,
+		Domain:   link.Domain,
+		Cause:    link.Cause.toProto(),
+	}
+
+	for _, f := range link.Frames {
+		msg.Frames = append(msg.Frames, &StackFrame{Function: f.Function, File: f.File, Line: int32(f.Line)})
+	}
+
+	for _, l := range link.Errors {
+		msg.Errors = append(msg.Errors, l.toProto())
+	}
+
+	return msg
+}
+
+func newLinkFromProto(msg *Link) *jsonLink {
+	if msg == nil {
+		return nil
+	}
+
+	link := &jsonLink{
+		Kind:      linkError,
+		Code:      int(msg.GetCode()),
+		HTTP:      int(msg.GetHttp()),
+		Message:   msg.GetMessage(),
+		Reference: msg.GetRef(),
+		GRPC:      msg.GetGrpc(),
+		Error:     msg.GetInternal(),
+		Redacted:  msg.GetRedacted(),
+		Metadata:  msg.GetMetadata(),
+		Domain:    msg.GetDomain(),
+		Cause:     newLinkFromProto(msg.GetCause()),
+	}
+
+	for kind, k := range kinds {
+		if k == msg.GetKind() {
+			link.Kind = kind
+		}
+	}
+
+	for _, f := range msg.GetFrames() {
+		link.Frames = append(link.Frames, jsonFrame{f.GetFunction(), f.GetFile(), int(f.GetLine())})
+	}
+
+	for _, l := range msg.GetErrors() {
+		link.Errors = append(link.Errors, newLinkFromProto(l))
+	}
+
+	return link
+}
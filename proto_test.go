@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	err := Join(
+		WithFields(WrapCode(WithMessage(NewWithStack("connection refused"), "select user"), ErrInvalidJSON, "load user"), "uid", 1),
+		New("plain"),
+	)
+
+	msg := ToProto(err)
+	assert.Equal(t, Link_KIND_JOIN, msg.GetKind())
+	assert.Equal(t, int32(ErrInvalidJSON), msg.GetCode())
+
+	data, merr := proto.Marshal(msg)
+	assert.NoError(t, merr)
+
+	decoded := new(Link)
+	assert.NoError(t, proto.Unmarshal(data, decoded))
+
+	got := FromProto(decoded)
+	assert.Equal(t, err.Error(), got.Error())
+	assert.True(t, IsCode(got, ErrInvalidJSON))
+	assert.Equal(t, ParseCoder(err), ParseCoder(got))
+	assert.Equal(t, "1", Fields(Errors(got)[0])["uid"])
+	assert.Equal(t, fmt.Sprintf("%+v", err), fmt.Sprintf("%+v", got))
+	assert.True(t, proto.Equal(msg, ToProto(got)))
+
+	assert.Nil(t, ToProto(nil))
+	assert.Nil(t, FromProto(nil))
+}
+
+func TestProtoPackage(t *testing.T) {
+	assert.Equal(t, "leo.errors.v1.Status", string((&Status{}).ProtoReflect().Descriptor().FullName()))
+	assert.Equal(t, "leo.errors.v1.Link", string((&Link{}).ProtoReflect().Descriptor().FullName()))
+}
@@ -0,0 +1,42 @@
+package errors
+
+import "net/http"
+
+// debug reports whether the renderers expose the internal messages.
+var debug = false
+
+// SetDebug set whether WriteHTTP, GRPCStatus and the gRPC interceptors expose the
+// internal message of the errors, and the stack for gRPC, besides the public one.
+// It must not be enabled where clients are untrusted.
+func SetDebug(enabled bool) {
+	debug = enabled
+}
+
+// Public returns the message of err which may be shown to clients: the external
+// message of its Coder, or the HTTP status text if the Coder has none. The messages
+// of the chain are never exposed, whatever the order of wrapping.
+// It returns "" if err is nil.
+func Public(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return publicMessage(ParseCoder(err))
+}
+
+// publicMessage returns the external message of coder, or its HTTP status text.
+func publicMessage(coder Coder) string {
+	if msg := coder.String(); msg != "" {
+		return msg
+	}
+
+	return http.StatusText(coder.HTTPStatus())
+}
+
+// Internal returns the diagnostic text of err for developers: the internal messages
+// of its chain joined by ": ", e.g. "load user: select user: connection refused".
+// The errors of an aggregate are joined by "; ".
+// It returns "" if err is nil.
+func Internal(err error) string {
+	return chainText(err, false)
+}
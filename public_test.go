@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestPublicInternal(t *testing.T) {
+	dsn := New("dial postgres://admin:secret@db:5432: connection refused")
+
+	tests := []struct {
+		name         string
+		err          error
+		wantPublic   string
+		wantInternal string
+	}{
+		{"nil", nil, "", ""},
+		{"std", dsn, UnknownCoder.String(), dsn.Error()},
+		{"message over code", WithMessage(WrapCode(dsn, ErrUserNoRegister, "load user"), "get user"),
+			"user no register, go to create", "get user: load user: " + dsn.Error()},
+		{"stack over code", WrapStack(WithCode(dsn, ErrEOF), "read"), "End of input", "read: " + dsn.Error()},
+		{"WithStack over NewWithCode", WithStack(NewWithCode(ErrEOF, "user %d not found", 42)), "End of input", "user 42 not found"},
+		{"fields", WithFields(NewWithCode(ErrEOF, "uid %d", 42), "uid", 42), "End of input", "uid 42"},
+		{"join", Join(NewWithCode(ErrEOF, "a"), WithMessage(dsn, "b")), "End of input", "a; b: " + dsn.Error()},
+		{"unregistered without message", NewWithCode(9101, "x"), UnknownCoder.String(), "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPublic, Public(tt.err))
+			assert.Equal(t, tt.wantInternal, Internal(tt.err))
+		})
+	}
+
+	Register(defaultCoder{9102, http.StatusConflict, "", ""})
+	assert.Equal(t, "Conflict", Public(NewWithCode(9102, "x")))
+}
+
+func TestDebug(t *testing.T) {
+	err := WrapCode(New("dsn leaked"), ErrUserNoRegister, "load user")
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, nil, err)
+	assert.NotContains(t, rec.Body.String(), "dsn leaked")
+	assert.NotContains(t, GRPCStatus(err).String(), "dsn leaked")
+	assert.Len(t, GRPCStatus(err).Details(), 1)
+
+	SetDebug(true)
+	defer SetDebug(false)
+
+	rec = httptest.NewRecorder()
+	WriteHTTP(rec, nil, err)
+	var herr HTTPError
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &herr))
+	assert.Equal(t, "user no register, go to create", herr.Message)
+	assert.Equal(t, "load user: dsn leaked", herr.Detail)
+
+	s := GRPCStatus(err)
+	assert.Equal(t, "user no register, go to create", s.Message())
+	assert.Len(t, s.Details(), 2)
+	info, ok := s.Details()[1].(*errdetails.DebugInfo)
+	assert.True(t, ok)
+	assert.Equal(t, "load user: dsn leaked", info.GetDetail())
+	assert.Contains(t, info.GetStackEntries()[0], "github.com/go-leo/errors.TestDebug ")
+}
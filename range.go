@@ -0,0 +1,235 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// CodeRange is a block of codes claimed by a service or a module, see RegisterRange.
+type CodeRange struct {
+	registry *Registry
+	name     string
+	lo, hi   int
+}
+
+// Name returns the owner of the range.
+func (cr *CodeRange) Name() string {
+	return cr.name
+}
+
+// Lo returns the first code of the range.
+func (cr *CodeRange) Lo() int {
+	return cr.lo
+}
+
+// Hi returns the last code of the range.
+func (cr *CodeRange) Hi() int {
+	return cr.hi
+}
+
+// Contains reports whether code is in the range.
+func (cr *CodeRange) Contains(code int) bool {
+	return cr.lo <= code && code <= cr.hi
+}
+
+// String returns the range in the form of "name[lo, hi]".
+func (cr *CodeRange) String() string {
+	return fmt.Sprintf("%s[%d, %d]", cr.name, cr.lo, cr.hi)
+}
+
+// Register register a code of the range in its registry.
+// It will panic when the code is out of the range, and overrid the exist code.
+func (cr *CodeRange) Register(coder Coder) {
+	cr.check(coder)
+	cr.registry.register(coder, cr, false)
+}
+
+// MustRegister register a code of the range in its registry.
+// It will panic when the code is out of the range, or the same Code already exist.
+func (cr *CodeRange) MustRegister(coder Coder) {
+	cr.check(coder)
+	cr.registry.register(coder, cr, true)
+}
+
+func (cr *CodeRange) check(coder Coder) {
+	if coder.Code() == 0 {
+		panic("code `0` is reserved by `github.com/panda/errors` as unknownCode error code")
+	}
+	if !cr.Contains(coder.Code()) {
+		panic(fmt.Sprintf("code: %d is out of range %s", coder.Code(), cr))
+	}
+}
+
+// RegisterRange claims the codes from lo to hi for name in DefaultRegistry.
+// It will panic when the range overlaps a range already claimed.
+func RegisterRange(name string, lo, hi int) *CodeRange {
+	return DefaultRegistry.RegisterRange(name, lo, hi)
+}
+
+// SetStrictRanges set whether DefaultRegistry rejects the codes out of the claimed
+// ranges, see Registry.SetStrictRanges.
+func SetStrictRanges(enabled bool) {
+	DefaultRegistry.SetStrictRanges(enabled)
+}
+
+// RegisterRange claims the codes from lo to hi for name, e.g. 100000 to 100099
+// for the base module and 110000 to 110099 for the account one. The codes of the
+// range are registered through the returned CodeRange, which rejects the codes of
+// the other owners.
+// It will panic when lo is greater than hi, or the range overlaps a range already
+// claimed, so the collisions between teams are found as soon as the ranges are
+// claimed rather than when a code happens to be registered twice.
+func (r *Registry) RegisterRange(name string, lo, hi int) *CodeRange {
+	if lo > hi {
+		panic(fmt.Sprintf("range: %s[%d, %d] is empty", name, lo, hi))
+	}
+
+	cr := &CodeRange{registry: r, name: name, lo: lo, hi: hi}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.load()
+	for _, claimed := range old.ranges {
+		if claimed.lo <= hi && lo <= claimed.hi {
+			panic(fmt.Sprintf("range: %s overlaps %s", cr, claimed))
+		}
+	}
+
+	ranges := make([]*CodeRange, 0, len(old.ranges)+1)
+	ranges = append(ranges, old.ranges...)
+	ranges = append(ranges, cr)
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+
+	next := *old
+	next.ranges = ranges
+	r.state.Store(&next)
+
+	return cr
+}
+
+// SetStrictRanges set whether Register and MustRegister reject, by panicking, the
+// codes out of the claimed ranges, and the codes of a claimed range which are not
+// registered through its CodeRange, so an owner can't register the codes of another
+// one. It is disabled by default. It should be enabled before the codes are
+// registered, the codes already registered are not checked.
+func (r *Registry) SetStrictRanges(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := *r.load()
+	next.strict = enabled
+	r.state.Store(&next)
+}
+
+// checkRange panics when strict mode is on and code is out of the claimed ranges,
+// or is registered by another owner than the one of its range, nil for Register.
+// r.mu must be held.
+func (r *Registry) checkRange(code int, owner *CodeRange) {
+	state := r.load()
+	if !state.strict {
+		return
+	}
+
+	switch cr := state.rangeOf(code); {
+	case cr == nil:
+		panic(fmt.Sprintf("code: %d is out of the claimed ranges", code))
+	case cr != owner:
+		panic(fmt.Sprintf("code: %d of range %s is not registered through it", code, cr))
+	}
+}
+
+// rangeOf returns the claimed range of code, nil if there is none.
+func (s *registryState) rangeOf(code int) *CodeRange {
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].hi >= code })
+	if i < len(s.ranges) && s.ranges[i].Contains(code) {
+		return s.ranges[i]
+	}
+
+	return nil
+}
+
+// RangeUsage is the utilization of a claimed range.
+type RangeUsage struct {
+	Name string
+	Lo   int
+	Hi   int
+	// Used is the number of registered codes of the range.
+	Used int
+}
+
+// Size returns the number of codes of the range.
+func (u RangeUsage) Size() int {
+	return u.Hi - u.Lo + 1
+}
+
+// Utilization returns the ratio of the registered codes of the range.
+func (u RangeUsage) Utilization() float64 {
+	return float64(u.Used) / float64(u.Size())
+}
+
+// RangeReport reports the claimed ranges of a registry and their utilization.
+type RangeReport struct {
+	// Ranges are ordered by Lo.
+	Ranges []RangeUsage
+	// Unclaimed are the registered codes out of the claimed ranges, in order.
+	Unclaimed []int
+}
+
+// String returns the report as a table, e.g.
+//
+//	RANGE    LO      HI      USED  UTILIZATION
+//	base     100000  100099  3     3.0%
+//	account  110000  110099  0     0.0%
+//	unclaimed: 1, 2, 3
+//
+//nolint: errcheck // a strings.Builder never fails
+func (rr RangeReport) String() string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANGE\tLO\tHI\tUSED\tUTILIZATION")
+	for _, u := range rr.Ranges {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f%%\n", u.Name, u.Lo, u.Hi, u.Used, u.Utilization()*100)
+	}
+	w.Flush()
+
+	if len(rr.Unclaimed) > 0 {
+		codes := make([]string, 0, len(rr.Unclaimed))
+		for _, code := range rr.Unclaimed {
+			codes = append(codes, fmt.Sprint(code))
+		}
+		fmt.Fprintf(&b, "unclaimed: %s\n", strings.Join(codes, ", "))
+	}
+
+	return b.String()
+}
+
+// Ranges returns the claimed ranges of DefaultRegistry and their utilization.
+func Ranges() RangeReport {
+	return DefaultRegistry.Ranges()
+}
+
+// Ranges returns the claimed ranges of r, their owners and their utilization.
+func (r *Registry) Ranges() RangeReport {
+	state := r.load()
+
+	report := RangeReport{Ranges: make([]RangeUsage, 0, len(state.ranges))}
+	used := make(map[*CodeRange]int, len(state.ranges))
+	for code := range state.codes {
+		if cr := state.rangeOf(code); cr != nil {
+			used[cr]++
+		} else {
+			report.Unclaimed = append(report.Unclaimed, code)
+		}
+	}
+	sort.Ints(report.Unclaimed)
+
+	for _, cr := range state.ranges {
+		report.Ranges = append(report.Ranges, RangeUsage{Name: cr.name, Lo: cr.lo, Hi: cr.hi, Used: used[cr]})
+	}
+
+	return report
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRange(t *testing.T) {
+	r := NewRegistry("")
+	base := r.RegisterRange("base", 100000, 100099)
+	account := r.RegisterRange("account", 110000, 110099)
+
+	base.MustRegister(defaultCoder{100001, 400, "Invalid", ""})
+	account.Register(defaultCoder{110001, 404, "Account not found", ""})
+	assert.Equal(t, 404, r.GetCoder(110001).HTTPStatus())
+
+	// the codes of another owner are rejected
+	assert.Panics(t, func() { base.Register(defaultCoder{110002, 400, "Invalid", ""}) })
+	assert.Panics(t, func() { account.MustRegister(defaultCoder{100002, 400, "Invalid", ""}) })
+
+	// the overlaps are found when the ranges are claimed
+	assert.Panics(t, func() { r.RegisterRange("payment", 100050, 100149) })
+	assert.Panics(t, func() { r.RegisterRange("payment", 99000, 120000) })
+	assert.Panics(t, func() { r.RegisterRange("payment", 120099, 120000) })
+	assert.NotPanics(t, func() { r.RegisterRange("payment", 100100, 100199) })
+}
+
+func TestStrictRanges(t *testing.T) {
+	r := NewRegistry("")
+	base := r.RegisterRange("base", 100000, 100099)
+	account := r.RegisterRange("account", 110000, 110099)
+
+	// out of strict mode any code is accepted
+	r.Register(defaultCoder{42, 400, "Invalid", ""})
+	r.Register(defaultCoder{100098, 400, "Invalid", ""})
+
+	r.SetStrictRanges(true)
+	assert.Panics(t, func() { r.Register(defaultCoder{43, 400, "Invalid", ""}) })
+	assert.Panics(t, func() { r.MustRegister(defaultCoder{200000, 400, "Invalid", ""}) })
+	assert.NotPanics(t, func() { base.MustRegister(defaultCoder{100000, 400, "Invalid", ""}) })
+	assert.Equal(t, UnknownCoder, r.GetCoder(43))
+
+	// the codes of a range are registered by its owner only
+	assert.Panics(t, func() { r.Register(defaultCoder{110001, 400, "Invalid", ""}) })
+	assert.Panics(t, func() { r.MustRegister(defaultCoder{100001, 400, "Invalid", ""}) })
+	assert.Panics(t, func() { base.Register(defaultCoder{110001, 400, "Invalid", ""}) })
+	assert.Equal(t, UnknownCoder, r.GetCoder(110001))
+	assert.NotPanics(t, func() { account.Register(defaultCoder{110001, 404, "Account not found", ""}) })
+	assert.Equal(t, 404, r.GetCoder(110001).HTTPStatus())
+}
+
+func TestRangeReport(t *testing.T) {
+	r := NewRegistry("")
+	account := r.RegisterRange("account", 110000, 110099)
+	base := r.RegisterRange("base", 100000, 100099)
+	base.Register(defaultCoder{100001, 400, "Invalid", ""})
+	base.Register(defaultCoder{100002, 400, "Invalid", ""})
+	base.Register(defaultCoder{100099, 400, "Invalid", ""})
+	r.Register(defaultCoder{7, 400, "Invalid", ""})
+	r.Register(defaultCoder{3, 400, "Invalid", ""})
+
+	report := r.Ranges()
+	assert.Equal(t, []RangeUsage{
+		{Name: "base", Lo: 100000, Hi: 100099, Used: 3},
+		{Name: account.Name(), Lo: account.Lo(), Hi: account.Hi(), Used: 0},
+	}, report.Ranges)
+	assert.Equal(t, []int{3, 7}, report.Unclaimed)
+	assert.Equal(t, 0.03, report.Ranges[0].Utilization())
+	assert.Equal(t, ""+
+		"RANGE    LO      HI      USED  UTILIZATION\n"+
+		"base     100000  100099  3     3.0%\n"+
+		"account  110000  110099  0     0.0%\n"+
+		"unclaimed: 3, 7\n", report.String())
+}
@@ -0,0 +1,233 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// redactedMark replaces the sensitive arguments in the redacted rendering.
+const redactedMark = "‹×›"
+
+// redactUnmarked reports whether the format arguments which are marked neither
+// by Safe nor by Redact are sensitive.
+var redactUnmarked = false
+
+// SetRedactUnmarked set whether the format arguments marked neither by Safe nor
+// by Redact are sensitive, and whether so are the messages of the errors of other
+// packages. They are safe by default. It should be set before any error is created.
+func SetRedactUnmarked(enabled bool) {
+	redactUnmarked = enabled
+}
+
+// Safe marks a format argument of NewWithCode, WrapCodef, WithMessagef etc. as
+// safe, it is kept in the redacted rendering of the error.
+func Safe(v interface{}) fmt.Formatter {
+	return safeArg{v}
+}
+
+// Redact marks a format argument of NewWithCode, WrapCodef, WithMessagef etc. as
+// sensitive, e.g. an email or a token. It is replaced by ‹×› in the redacted
+// rendering of the error, see Redacted.
+func Redact(v interface{}) fmt.Formatter {
+	return redactArg{v}
+}
+
+type safeArg struct {
+	v interface{}
+}
+
+// Format formats the value as if it was not marked.
+func (a safeArg) Format(s fmt.State, verb rune) {
+	fmt.Fprintf(s, fmt.FormatString(s, verb), a.v)
+}
+
+type redactArg struct {
+	v interface{}
+}
+
+// Format formats the value as if it was not marked.
+func (a redactArg) Format(s fmt.State, verb rune) {
+	fmt.Fprintf(s, fmt.FormatString(s, verb), a.v)
+}
+
+// Redacted returns the diagnostic text of err like Internal does, with the sensitive
+// format arguments replaced by ‹×›. It is the form of the errors for shared log
+// storage: the log sinks and exporters use it by default, see SetLogRedacted.
+// It returns "" if err is nil.
+func Redacted(err error) string {
+	return chainText(err, true)
+}
+
+// errorf formats an error like fmt.Errorf, keeping the redacted rendering of the
+// message alongside the full one if they differ.
+func errorf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+
+	redacted := err.Error()
+	if len(args) > 0 {
+		// the redacted arguments are no errors to wrap any more
+		redacted = fmt.Sprintf(strings.ReplaceAll(format, "%w", "%v"), redactArgs(args)...)
+	}
+
+	if redacted == err.Error() && !redactUnmarked {
+		return err
+	}
+
+	r := &redactable{error: err, redacted: redacted}
+	if _, ok := err.(interface{ Unwrap() []error }); ok {
+		return &redactableJoin{r}
+	}
+
+	return r
+}
+
+// redactArgs returns args with the sensitive ones replaced by ‹×›, and the errors
+// replaced by their redacted rendering.
+func redactArgs(args []interface{}) []interface{} {
+	ret := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case safeArg:
+			ret[i] = a.v
+		case redactArg:
+			ret[i] = verbatim(redactedMark)
+		case error:
+			ret[i] = verbatim(redactedMark)
+			if !redactUnmarked {
+				ret[i] = verbatim(Redacted(a))
+			}
+		default:
+			ret[i] = arg
+			if redactUnmarked {
+				ret[i] = verbatim(redactedMark)
+			}
+		}
+	}
+
+	return ret
+}
+
+// verbatim is a format argument written as is, whatever the verb and the flags.
+type verbatim string
+
+//nolint: errcheck // WriteString could no check in pkg
+func (v verbatim) Format(s fmt.State, _ rune) {
+	io.WriteString(s, string(v))
+}
+
+// redactedText returns the redacted rendering of the message of err itself, as
+// opposed to the messages of its causes.
+func redactedText(err error) string {
+	switch e := err.(type) {
+	case *redactable:
+		return e.redacted
+	case *redactableJoin:
+		return e.redacted
+	case *withMessage:
+		return e.redactedMsg()
+	case *withCode:
+		return redactedText(e.err)
+	case *withStack:
+		return redactedText(e.error)
+	case *jsonError:
+		if e.redacted != "" {
+			return e.redacted
+		}
+	}
+
+	if redactUnmarked {
+		return redactedMark
+	}
+
+	return err.Error()
+}
+
+// logText returns the message of err itself for the log sinks, the redacted one
+// unless SetLogRedacted(false).
+func logText(err error) string {
+	if logRedacted {
+		return redactedText(err)
+	}
+
+	return err.Error()
+}
+
+// formatLog writes err in the log format of verb and the flags of s. The errors
+// which are no fmt.Formatter are written as their logText.
+//nolint: errcheck // WriteString could no check in pkg
+func formatLog(s fmt.State, verb rune, err error) {
+	if _, ok := err.(fmt.Formatter); ok {
+		fmt.Fprintf(s, fmt.FormatString(s, verb), err)
+		return
+	}
+
+	io.WriteString(s, logText(err))
+}
+
+// chainText returns the messages of err's chain joined by ": ", the ones of an
+// aggregate are joined by "; ". The messages are redacted if redact is true.
+func chainText(err error, redact bool) string {
+	text := func(err error) string {
+		if redact {
+			return redactedText(err)
+		}
+
+		return err.Error()
+	}
+
+	switch e := err.(type) {
+	case nil:
+		return ""
+	case *withCode:
+		// WithCode annotates its cause without a message of its own
+		if e.err == e.cause {
+			return chainText(e.cause, redact)
+		}
+
+		// WithStack on a coded error repeats the message of its cause
+		if wc := new(withCode); As(e.cause, &wc) && wc.err == e.err {
+			return chainText(e.cause, redact)
+		}
+
+		if e.cause == nil || e.remote {
+			return text(e.err)
+		}
+
+		return text(e.err) + ": " + chainText(e.cause, redact)
+	case *withStack:
+		return chainText(e.Cause(), redact)
+	case *withMessage:
+		return text(e) + ": " + chainText(e.cause, redact)
+	case *withFields:
+		return chainText(e.cause, redact)
+	case *joinError:
+		msgs := make([]string, 0, len(e.errs))
+		for _, err := range e.errs {
+			msgs = append(msgs, chainText(err, redact))
+		}
+
+		return strings.Join(msgs, "; ")
+	default:
+		return text(err)
+	}
+}
+
+// redactable is an error whose message has a redacted rendering.
+type redactable struct {
+	error
+	redacted string
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (e *redactable) Unwrap() error { return Unwrap(e.error) }
+
+// redactableJoin is a redactable wrapping several errors with %w.
+type redactableJoin struct {
+	*redactable
+}
+
+// Unwrap provides compatibility for Go 1.20 multi-error chains.
+func (e *redactableJoin) Unwrap() []error {
+	return e.error.(interface{ Unwrap() []error }).Unwrap()
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedacted(t *testing.T) {
+	email := "lk@example.com"
+
+	tests := []struct {
+		name         string
+		err          error
+		wantInternal string
+		wantRedacted string
+	}{
+		{"nil", nil, "", ""},
+		{"unmarked", New("uid: %d", 42), "uid: 42", "uid: 42"},
+		{"redact", NewWithCode(ErrUserNoRegister, "email: %s", Redact(email)),
+			"email: lk@example.com", "email: ‹×›"},
+		{"safe", NewWithStack("uid: %d, email: %s", Safe(42), Redact(email)),
+			"uid: 42, email: lk@example.com", "uid: 42, email: ‹×›"},
+		{"chain", WithMessagef(WrapCodef(New("token %q", Redact("t0k3n")), ErrEOF, "login %s", Redact(email)), "user %d", 1),
+			`user 1: login lk@example.com: token "t0k3n"`, "user 1: login ‹×›: token ‹×›"},
+		{"wrap stack", WrapStackf(New("dial"), "dsn %s", Redact("postgres://admin:secret@db")),
+			"dsn postgres://admin:secret@db: dial", "dsn ‹×›: dial"},
+		{"error arg", New("query: %w", New("token %s", Redact("t0k3n"))), "query: token t0k3n", "query: token ‹×›"},
+		{"option args", NewWithCodeX(ErrEOF, "email: %s", WithArgs(Redact(email))), "email: lk@example.com", "email: ‹×›"},
+		{"join", Join(New("a %s", Redact(email)), New("b")), "a lk@example.com; b", "a ‹×›; b"},
+		{"stack over code", WithStack(NewWithCode(ErrEOF, "email: %s", Redact(email))), "email: lk@example.com", "email: ‹×›"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantInternal, Internal(tt.err))
+			assert.Equal(t, tt.wantRedacted, Redacted(tt.err))
+		})
+	}
+}
+
+func TestRedactKeepsChain(t *testing.T) {
+	err := New("read %s: %w", Redact("secret.txt"), io.EOF)
+	assert.True(t, Is(err, io.EOF))
+	assert.Equal(t, "read secret.txt: EOF", err.Error())
+	assert.Equal(t, "read ‹×›: EOF", Redacted(err))
+
+	err = New("%w and %w", io.EOF, io.ErrUnexpectedEOF)
+	assert.True(t, Is(err, io.ErrUnexpectedEOF))
+
+	assert.Equal(t, "    42", fmt.Sprintf("%6d", Safe(42)))
+	assert.Equal(t, `"t0k3n"`, fmt.Sprintf("%q", Redact("t0k3n")))
+}
+
+func TestRedactUnmarked(t *testing.T) {
+	SetRedactUnmarked(true)
+	defer SetRedactUnmarked(false)
+
+	err := WrapCodef(io.EOF, ErrEOF, "uid %d, shard %d", 42, Safe(3))
+	assert.Equal(t, "uid ‹×›, shard 3: ‹×›", Redacted(err))
+	assert.Equal(t, "uid 42, shard 3: EOF", Internal(err))
+	assert.Equal(t, "static", Redacted(NewWithCode(ErrEOF, "static")))
+}
+
+func TestRedactedLogAndJSON(t *testing.T) {
+	err := WithMessagef(NewWithCode(ErrUserNoRegister, "email: %s", Redact("lk@example.com")), "token %s", Redact("t0k3n"))
+
+	assert.Equal(t, "token ‹×›", logValue(err).Group()[3].Value.String())
+
+	SetLogRedacted(false)
+	assert.Equal(t, "token t0k3n", logValue(err).Group()[3].Value.String())
+	SetLogRedacted(true)
+
+	// the codecs are lossless, they carry both the internal and the redacted messages
+	data, jerr := MarshalJSON(err)
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(data), `"error":"token t0k3n"`)
+	assert.Contains(t, string(data), `"redacted":"token ‹×›"`)
+	got, uerr := UnmarshalJSON(data)
+	assert.NoError(t, uerr)
+	assert.Equal(t, Internal(err), Internal(got))
+	assert.Equal(t, Redacted(err), Redacted(got))
+	assert.Equal(t, Internal(err), Internal(FromProto(ToProto(err))))
+	assert.Equal(t, Redacted(err), Redacted(FromProto(ToProto(err))))
+
+	var _ slog.LogValuer = err.(*withMessage)
+}
+
+func TestRedactedFormat(t *testing.T) {
+	coded := NewWithCode(ErrUserNoRegister, "email: %s", Redact("lk@example.com"))
+	stacked := WithMessagef(NewWithStack("token %s", Redact("t0k3n")), "login %s", Redact("lk@example.com"))
+
+	for _, format := range []string{"%-v", "%+v", "%#v", "%#-v", "%#+v"} {
+		for _, err := range []error{coded, stacked, Join(coded, stacked)} {
+			text := fmt.Sprintf(format, err)
+			assert.NotContains(t, text, "lk@example.com", format)
+			assert.NotContains(t, text, "t0k3n", format)
+		}
+	}
+	assert.Contains(t, fmt.Sprintf("%-v", coded), "email: ‹×›")
+	assert.Contains(t, fmt.Sprintf("%+v", stacked), "token ‹×›\n")
+
+	// the message of the program itself is not redacted
+	assert.Equal(t, "login lk@example.com", stacked.Error())
+	assert.Equal(t, "login lk@example.com", fmt.Sprintf("%v", stacked))
+
+	SetLogRedacted(false)
+	defer SetLogRedacted(true)
+	assert.Contains(t, fmt.Sprintf("%-v", coded), "email: lk@example.com")
+	assert.Contains(t, fmt.Sprintf("%+v", stacked), "token t0k3n\n")
+}
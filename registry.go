@@ -0,0 +1,379 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/status"
+)
+
+// Registry holds a set of Coders and the translations of their external messages.
+// Libraries can ship their own code set in a Registry of their own namespace, so
+// their codes don't collide with the ones of the application, and tests can use
+// isolated registries. The package functions Register, ParseCoder, GRPCStatus etc.
+// use DefaultRegistry. The errors created by the constructors of a Registry, or
+// with WithRegistry, carry their registry, so their codes are looked up in it
+// wherever they are parsed, formatted or rendered.
+//
+// A Registry is copy-on-write: the registrations copy the tables and publish the
+// copy, so the lookups on the paths of formatting, parsing and rendering errors
+// never lock, and may run concurrently with the registrations.
+type Registry struct {
+	namespace string
+
+	// mu serializes the registrations.
+	mu    sync.Mutex
+	state atomic.Value // *registryState
+}
+
+// registryState is a snapshot of the tables of a Registry, it is never modified
+// once published.
+type registryState struct {
+	codes        map[int]Coder
+	translations map[string]map[int]string
+
+	// ranges are the claimed ranges ordered by lo.
+	ranges []*CodeRange
+	strict bool
+}
+
+// DefaultRegistry is the registry of the package functions, and of the errors
+// created without WithRegistry.
+var DefaultRegistry = newRegistry("")
+
+var (
+	// registries contains the registries with a namespace, by namespace.
+	registries   = map[string]*Registry{}
+	registriesMu sync.Mutex
+)
+
+// NewRegistry returns an empty registry of namespace. The namespace is the domain
+// of the codes of the registry, which travels with the errors encoded by MarshalJSON
+// and ToProto, so they are looked up in the registry of the same namespace when
+// decoded. The registry of an empty namespace is anonymous, its errors are decoded
+// with DefaultRegistry.
+// It will panic when a registry of the same namespace already exist.
+func NewRegistry(namespace string) *Registry {
+	r := newRegistry(namespace)
+	if namespace == "" {
+		return r
+	}
+
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+
+	if _, ok := registries[namespace]; ok {
+		panic(fmt.Sprintf("registry: %s already exist", namespace))
+	}
+
+	registries[namespace] = r
+
+	return r
+}
+
+func newRegistry(namespace string) *Registry {
+	r := &Registry{namespace: namespace}
+	r.state.Store(&registryState{
+		codes:        map[int]Coder{},
+		translations: map[string]map[int]string{},
+	})
+
+	return r
+}
+
+// load returns the current snapshot of the tables of r.
+func (r *Registry) load() *registryState {
+	return r.state.Load().(*registryState)
+}
+
+// storeCodes publishes a copy of the tables of r with coders at once. r.mu must be held.
+func (r *Registry) storeCodes(coders ...Coder) {
+	old := r.load()
+
+	codes := make(map[int]Coder, len(old.codes)+len(coders))
+	for code, c := range old.codes {
+		codes[code] = c
+	}
+	for _, coder := range coders {
+		codes[coder.Code()] = coder
+	}
+
+	next := *old
+	next.codes = codes
+	r.state.Store(&next)
+}
+
+// unregister removes the registry of namespace, so a registry of the namespace may
+// be created again.
+func unregister(namespace string) {
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+
+	delete(registries, namespace)
+}
+
+// lookupRegistry returns the registry of namespace, nil if there is none.
+func lookupRegistry(namespace string) *Registry {
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+
+	return registries[namespace]
+}
+
+// Namespace returns the namespace of the registry.
+func (r *Registry) Namespace() string {
+	return r.namespace
+}
+
+// Register register a user define error code.
+// It will overrid the exist code. In strict mode, see SetStrictRanges, it will
+// panic when the code is out of the claimed ranges, or in a claimed range, whose
+// codes are registered through its CodeRange only.
+func (r *Registry) Register(coder Coder) {
+	if coder.Code() == 0 {
+		panic("code `0` is reserved by `github.com/panda/errors` as unknownCode error code")
+	}
+
+	r.register(coder, nil, false)
+}
+
+// MustRegister register a user define error code.
+// It will panic when the same Code already exist. In strict mode, see SetStrictRanges,
+// it will panic when the code is out of the claimed ranges, or in a claimed range,
+// whose codes are registered through its CodeRange only.
+func (r *Registry) MustRegister(coder Coder) {
+	if coder.Code() == 0 {
+		panic("code '0' is reserved by 'github.com/panda/errors' as ErrUnknown error code")
+	}
+
+	r.register(coder, nil, true)
+}
+
+// register registers coder on behalf of owner, the CodeRange registering it if any.
+// If must is true, it will panic when the same Code already exist.
+func (r *Registry) register(coder Coder, owner *CodeRange, must bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkRange(coder.Code(), owner)
+	if _, ok := r.load().codes[coder.Code()]; ok && must {
+		panic(fmt.Sprintf("code: %d already exist", coder.Code()))
+	}
+
+	r.storeCodes(coder)
+}
+
+// lookup returns the Coder registered for code.
+func (r *Registry) lookup(code int) (Coder, bool) {
+	coder, ok := r.load().codes[code]
+
+	return coder, ok
+}
+
+// GetCoder get Coder with code
+// not found return UnknownCoder.
+func (r *Registry) GetCoder(code int) Coder {
+	if coder, ok := r.lookup(code); ok {
+		return coder
+	}
+
+	return UnknownCoder
+}
+
+// ParseCoder is like the package function ParseCoder, but the codes of the errors
+// created without WithRegistry are looked up in r.
+func (r *Registry) ParseCoder(err error) Coder {
+	if err == nil {
+		return nil
+	}
+
+	if coder, ok := lookupCoder(err, r); ok {
+		return coder
+	}
+
+	ge, ok := receivedStatus(err)
+	if !ok {
+		return UnknownCoder
+	}
+
+	if coder, ok := statusCoder(ge); ok {
+		return coder
+	}
+
+	return UnknownCoder
+}
+
+// IsCode is like the package function IsCode, but the codes of the errors created
+// without WithRegistry are looked up in r.
+func (r *Registry) IsCode(err error, code int) bool {
+	if coder := r.ParseCoder(err); coder != nil && coder.Code() == code {
+		return true
+	}
+
+	for ; err != nil; err = Unwrap(err) {
+		if _, ok := err.(*withCode); ok {
+			break
+		}
+
+		if e, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range e.Unwrap() {
+				if r.IsCode(err, code) {
+					return true
+				}
+			}
+
+			break
+		}
+	}
+
+	return false
+}
+
+// GRPCStatus is like the package function GRPCStatus, but the codes of the errors
+// created without WithRegistry are looked up in r.
+func (r *Registry) GRPCStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	var c Coder = UnknownCoder
+	if coder, ok := lookupCoder(err, r); ok {
+		c = coder
+	} else if s, ok := receivedStatus(err); ok {
+		// a status received from another service is forwarded with its code
+		c = receivedCoder(s)
+	}
+
+	return newStatus(c, err)
+}
+
+// GRPCCodeStatus is like the package function GRPCCodeStatus, but code is looked up in r.
+func (r *Registry) GRPCCodeStatus(code int) *status.Status {
+	return newStatus(r.GetCoder(code), nil)
+}
+
+// NewWithCode is like the package function NewWithCode, but the error is created
+// with the registry r, see WithRegistry.
+func (r *Registry) NewWithCode(code int, format string, args ...interface{}) error {
+	return &withCode{
+		err:      errorf(format, args...),
+		code:     code,
+		stack:    callers(),
+		registry: r,
+	}
+}
+
+// WithCode is like the package function WithCode, but the error is created with
+// the registry r, see WithRegistry.
+func (r *Registry) WithCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withCode{
+		err:      err,
+		code:     code,
+		cause:    err,
+		stack:    callers(),
+		registry: r,
+	}
+}
+
+// WrapCode is like the package function WrapCode, but the error is created with
+// the registry r, see WithRegistry.
+func (r *Registry) WrapCode(err error, code int, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withCode{
+		err:      errorf(message),
+		code:     code,
+		cause:    err,
+		stack:    callers(),
+		registry: r,
+	}
+}
+
+// WrapCodef is like the package function WrapCodef, but the error is created with
+// the registry r, see WithRegistry.
+func (r *Registry) WrapCodef(err error, code int, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withCode{
+		err:      errorf(format, args...),
+		code:     code,
+		cause:    err,
+		stack:    callers(),
+		registry: r,
+	}
+}
+
+// RegisterTranslations register the external messages of codes translated into lang.
+// It will override the exist translations of the same codes.
+func (r *Registry) RegisterTranslations(lang string, messages map[int]string) {
+	lang = strings.ToLower(lang)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.load()
+
+	// only the catalog of lang is copied, the others are shared by the snapshots
+	catalog := make(map[int]string, len(old.translations[lang])+len(messages))
+	for code, message := range old.translations[lang] {
+		catalog[code] = message
+	}
+	for code, message := range messages {
+		catalog[code] = message
+	}
+
+	translations := make(map[string]map[int]string, len(old.translations)+1)
+	for l, c := range old.translations {
+		translations[l] = c
+	}
+	translations[lang] = catalog
+
+	next := *old
+	next.translations = translations
+	r.state.Store(&next)
+}
+
+// translation returns the external message of code translated into lang.
+func (r *Registry) translation(lang string, code int) (string, bool) {
+	message, ok := r.load().translations[lang][code]
+
+	return message, ok
+}
+
+// Localize is like the package function Localize, but the codes of the errors
+// created without WithRegistry are looked up in r.
+func (r *Registry) Localize(err error, lang string) string {
+	message, _ := localize(r.ParseCoder(err), registryOf(err, r), lang)
+
+	return message
+}
+
+// registryOf returns the registry of the first withCode in err's chain, r if it
+// has none.
+func registryOf(err error, r *Registry) *Registry {
+	for ; err != nil; err = Unwrap(err) {
+		if w, ok := err.(*withCode); ok {
+			if w.registry != nil {
+				return w.registry
+			}
+
+			break
+		}
+	}
+
+	return r
+}
+
+func init() {
+	DefaultRegistry.storeCodes(UnknownCoder)
+}
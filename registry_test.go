@@ -0,0 +1,167 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+// newTestRegistry returns a registry of namespace, which is removed when the test ends.
+func newTestRegistry(t *testing.T, namespace string) *Registry {
+	t.Helper()
+
+	r := NewRegistry(namespace)
+	t.Cleanup(func() { unregister(namespace) })
+
+	return r
+}
+
+func TestRegistryIsolated(t *testing.T) {
+	r := NewRegistry("")
+	r.MustRegister(defaultCoder{100001, 409, "Conflict", ""})
+
+	assert.Equal(t, UnknownCoder, GetCoder(100001))
+	assert.Equal(t, 409, r.GetCoder(100001).HTTPStatus())
+
+	// the errors created without WithRegistry are looked up in the given registry
+	err := NewWithCode(100001, "conflict")
+	assert.Equal(t, UnknownCoder, ParseCoder(err))
+	assert.Equal(t, 409, r.ParseCoder(err).HTTPStatus())
+	assert.True(t, r.IsCode(err, 100001))
+	assert.Equal(t, codes.Internal, GRPCStatus(err).Code())
+	assert.Equal(t, codes.Aborted, r.GRPCStatus(err).Code())
+	assert.Equal(t, codes.Aborted, r.GRPCCodeStatus(100001).Code())
+
+	// the errors created by r are looked up in r everywhere
+	bound := []error{
+		r.NewWithCode(100001, "conflict"),
+		r.WithCode(New("conflict"), 100001),
+		r.WrapCode(New("conflict"), 100001, "wrapped"),
+		r.WrapCodef(New("conflict"), 100001, "wrapped %d", 1),
+	}
+	for _, err := range bound {
+		assert.Equal(t, 409, ParseCoder(err).HTTPStatus())
+		assert.Equal(t, 409, r.ParseCoder(err).HTTPStatus())
+		assert.Equal(t, "Conflict", fmt.Sprint(err))
+		assert.Contains(t, fmt.Sprintf("%-v", err), "(100001) Conflict")
+		assert.Equal(t, codes.Aborted, GRPCStatus(err).Code())
+	}
+
+	assert.Nil(t, r.WithCode(nil, 100001))
+	assert.Nil(t, r.WrapCode(nil, 100001, "wrapped"))
+	assert.Nil(t, r.WrapCodef(nil, 100001, "wrapped"))
+}
+
+func TestRegistrySameCode(t *testing.T) {
+	a := newTestRegistry(t, "test.registry/a")
+	b := newTestRegistry(t, "test.registry/b")
+
+	// the same code in two namespaces doesn't panic
+	a.MustRegister(defaultCoder{100001, 400, "Invalid A", ""})
+	b.MustRegister(defaultCoder{100001, 404, "Missing B", ""})
+
+	errA := NewWithCodeX(100001, "a", WithRegistry(a))
+	errB := b.WrapCode(NewWithCodeX(100001, "b", WithRegistry(b)), 100001, "again")
+
+	// the registry of the error wins over the given one
+	assert.Equal(t, 400, ParseCoder(errA).HTTPStatus())
+	assert.Equal(t, 400, b.ParseCoder(errA).HTTPStatus())
+	assert.Equal(t, 404, ParseCoder(errB).HTTPStatus())
+	assert.Equal(t, 404, a.ParseCoder(errB).HTTPStatus())
+	assert.Equal(t, "Invalid A", fmt.Sprintf("%v", errA))
+	assert.Equal(t, "Missing B", fmt.Sprintf("%v", errB))
+	assert.Equal(t, codes.NotFound, GRPCStatus(WithStack(errB)).Code())
+	assert.True(t, IsCode(WithMessage(errA, "wrapped"), 100001))
+}
+
+func TestRegistryDuplicateNamespace(t *testing.T) {
+	newTestRegistry(t, "test.registry/dup")
+
+	assert.Panics(t, func() { NewRegistry("test.registry/dup") })
+	assert.NotPanics(t, func() { NewRegistry("") })
+}
+
+func TestRegistryLocalize(t *testing.T) {
+	r := NewRegistry("")
+	r.Register(defaultCoder{100002, 403, "Forbidden", ""})
+	r.RegisterTranslations("zh-CN", map[int]string{100002: "禁止访问"})
+
+	err := NewWithCodeX(100002, "forbidden", WithRegistry(r))
+	assert.Equal(t, "禁止访问", Localize(err, "zh-CN"))
+	assert.Equal(t, "禁止访问", LocalizedGRPCStatus(err, "zh-CN").Message())
+	assert.Equal(t, "Forbidden", Localize(err, "en"))
+}
+
+func TestRegistryJSONDomain(t *testing.T) {
+	r := newTestRegistry(t, "test.registry/json")
+	r.Register(defaultCoder{100003, 429, "Too many requests", ""})
+
+	data, jerr := MarshalJSON(NewWithCodeX(100003, "slow down", WithRegistry(r)))
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(data), `"domain":"test.registry/json"`)
+
+	// the error is rebuilt in the registry of its domain
+	got, err := UnmarshalJSON(data)
+	assert.NoError(t, err)
+	assert.True(t, IsCode(got, 100003))
+	assert.Equal(t, 429, ParseCoder(got).HTTPStatus())
+	assert.Equal(t, r, registryOf(got, DefaultRegistry))
+
+	again, jerr := MarshalJSON(got)
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(again), `"domain":"test.registry/json"`)
+}
+
+// TestRegistryConcurrent registers codes while errors are formatted, parsed
+// and rendered. It is meant to be run with -race. The codes are registered in a
+// registry of the test only, the package functions reading DefaultRegistry are
+// called with errors of that registry.
+func TestRegistryConcurrent(t *testing.T) {
+	r := newTestRegistry(t, "test.registry/concurrent")
+	const n = 200
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				code := 200000 + w*n + i
+				r.Register(defaultCoder{code, 400, "Bad request", ""})
+				r.RegisterTranslations("zh-CN", map[int]string{code: "错误请求"})
+			}
+		}(w)
+	}
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				code := 200000 + (g%4)*n + i
+				err := WithMessage(NewWithCodeX(code, "failed", WithRegistry(r)), "wrapped")
+				plain := NewWithCodeX(code, "failed", WithRegistry(r))
+
+				_ = fmt.Sprintf("%+v", err)
+				_ = fmt.Sprintf("%#-v", plain)
+				_ = ParseCoder(err)
+				_ = r.ParseCoder(plain)
+				_ = IsCode(plain, code)
+				_ = GRPCStatus(err)
+				_ = r.GRPCCodeStatus(code)
+				_ = Localize(err, "zh-CN")
+				_, _ = MarshalJSON(err)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 400, r.GetCoder(200000+4*n-1).HTTPStatus())
+	assert.Equal(t, 400, ParseCoder(NewWithCodeX(200000+4*n-1, "failed", WithRegistry(r))).HTTPStatus())
+	assert.Equal(t, UnknownCoder, GetCoder(200000+4*n-1))
+	assert.Equal(t, "错误请求", Localize(NewWithCodeX(200000, "failed", WithRegistry(r)), "zh-CN"))
+}
@@ -0,0 +1,197 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// logStack reports whether LogValue emits the top stack frames.
+var logStack = false
+
+// logRedacted reports whether the log sinks emit the redacted internal messages,
+// see SetLogRedacted.
+var logRedacted = true
+
+// SetLogStack set whether the slog.Value of an error carries the top stack
+// frames, up to the depth set by SetMaxStackPrintDepth.
+func SetLogStack(enabled bool) {
+	logStack = enabled
+}
+
+// SetLogRedacted set whether the log sinks carry the redacted internal messages,
+// see Redacted, or the full ones. They are redacted by default.
+// The log sinks are:
+//   - the slog.Value of the errors,
+//   - the '%-v', '%+v' and '%#v' formats.
+//
+// Error(), the '%s' and '%v' formats and Internal are the full messages for the
+// program itself, they are not redacted. MarshalJSON and ToProto persist and
+// transport the errors, they carry both the full and the redacted messages.
+func SetLogRedacted(enabled bool) {
+	logRedacted = enabled
+}
+
+// LogValue implements slog.LogValuer.
+func (w *withCode) LogValue() slog.Value { return logValue(w) }
+
+// LogValue implements slog.LogValuer.
+func (w *withStack) LogValue() slog.Value { return logValue(w) }
+
+// LogValue implements slog.LogValuer.
+func (w *withMessage) LogValue() slog.Value { return logValue(w) }
+
+// LogValue implements slog.LogValuer.
+func (w *withFields) LogValue() slog.Value { return logValue(w) }
+
+// LogValue implements slog.LogValuer.
+func (e *joinError) LogValue() slog.Value { return logValue(e) }
+
+// logValue returns a group of the code, HTTP status, external message,
+// internal message, metadata and optionally the stack of err.
+// The errors of an aggregate are grouped under "errors".
+func logValue(err error) slog.Value {
+	message := internalMessage(err)
+	if logRedacted {
+		message = redactedMessage(err)
+	}
+
+	coder := ParseCoder(err)
+	attrs := []slog.Attr{
+		slog.Int("code", coder.Code()),
+		slog.Int("http", coder.HTTPStatus()),
+		slog.String("message", coder.String()),
+		slog.String("error", message),
+	}
+
+	if fields := Fields(err); len(fields) > 0 {
+		md := make([]slog.Attr, 0, len(fields))
+		for k, v := range fields {
+			md = append(md, slog.Any(k, v))
+		}
+		attrs = append(attrs, slog.Attr{Key: "metadata", Value: slog.GroupValue(md...)})
+	}
+
+	if logStack {
+		if frames := topFrames(err, maxStackPrintDepth); len(frames) > 0 {
+			attrs = append(attrs, slog.Any("stack", frames))
+		}
+	}
+
+	for e := err; e != nil; e = Unwrap(e) {
+		if m, ok := e.(interface{ Unwrap() []error }); ok {
+			errs := m.Unwrap()
+			group := make([]slog.Attr, 0, len(errs))
+			for i, err := range errs {
+				group = append(group, slog.Any(strconv.Itoa(i), err))
+			}
+			attrs = append(attrs, slog.Attr{Key: "errors", Value: slog.GroupValue(group...)})
+
+			break
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// topFrames returns at most depth frames of the first stack in err's chain,
+// formatted as "function file:line".
+func topFrames(err error, depth int) []string {
+	var (
+		st   *stack
+		skip int
+	)
+
+	for e := err; e != nil && st == nil; e = Unwrap(e) {
+		switch e := e.(type) {
+		case *withCode:
+			st, skip = e.stack, e.skipDepth
+		case *withStack:
+			st = e.stack
+		}
+	}
+
+	if st.size() <= skip {
+		return nil
+	}
+
+	syms := visibleSymbols(st.slice(skip, st.size()).symbols())
+	if len(syms) > depth {
+		syms = syms[:depth]
+	}
+
+	frames := make([]string, 0, len(syms))
+	for _, sym := range syms {
+		frames = append(frames, fmt.Sprintf("%s %s:%d", sym.function, sym.path(), sym.line))
+	}
+
+	return frames
+}
+
+// NewLogHandler returns a slog.Handler which promotes the code of the first
+// error attribute of a record to a top-level "code" attribute, and picks the
+// level of the record from the HTTP status class of the code: LevelError for
+// 5xx and LevelWarn for 4xx. Records are handled by h.
+func NewLogHandler(h slog.Handler) slog.Handler {
+	return &logHandler{h}
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+// Enabled reports whether h handles records at level, or at a level
+// the record may be promoted to.
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.Handler.Enabled(ctx, level) || h.Handler.Enabled(ctx, slog.LevelError)
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		if kind := a.Value.Kind(); kind == slog.KindAny || kind == slog.KindLogValuer {
+			err, _ = a.Value.Any().(error)
+		}
+
+		return err == nil
+	})
+
+	if err == nil {
+		if !h.Handler.Enabled(ctx, r.Level) {
+			return nil
+		}
+
+		return h.Handler.Handle(ctx, r)
+	}
+
+	coder := ParseCoder(err)
+	level := r.Level
+	switch status := coder.HTTPStatus(); {
+	case status >= 500:
+		level = slog.LevelError
+	case status >= 400:
+		level = slog.LevelWarn
+	}
+
+	if !h.Handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	nr := slog.NewRecord(r.Time, level, r.Message, r.PC)
+	nr.AddAttrs(slog.Int("code", coder.Code()))
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(a)
+		return true
+	})
+
+	return h.Handler.Handle(ctx, nr)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{h.Handler.WithGroup(name)}
+}
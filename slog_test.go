@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func logJSON(t *testing.T, h func(*bytes.Buffer) slog.Handler, level slog.Level, err error) map[string]interface{} {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	slog.New(h(buf)).Log(context.Background(), level, "request failed", slog.Any("err", err))
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func jsonHandler(buf *bytes.Buffer) slog.Handler {
+	return slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+func TestLogValue(t *testing.T) {
+	err := WithFields(WithMessage(NewWithCode(ErrUserNoRegister, "uid %d", 42), "get user"), "uid", 42)
+
+	m := logJSON(t, jsonHandler, slog.LevelInfo, err)
+	assert.Equal(t, map[string]interface{}{
+		"code":     float64(ErrUserNoRegister),
+		"http":     float64(400),
+		"message":  "user no register, go to create",
+		"error":    "get user",
+		"metadata": map[string]interface{}{"uid": float64(42)},
+	}, m["err"])
+
+	SetLogStack(true)
+	defer SetLogStack(false)
+
+	m = logJSON(t, jsonHandler, slog.LevelInfo, NewWithStack("database conn failed!"))
+	stack := m["err"].(map[string]interface{})["stack"].([]interface{})
+	assert.Len(t, stack, maxStackPrintDepth)
+	assert.True(t, strings.HasPrefix(stack[0].(string), "github.com/go-leo/errors.TestLogValue "))
+}
+
+func TestLogValueJoin(t *testing.T) {
+	err := Join(NewWithCode(ErrUserNoRegister, "uid %d", 42), New("std error"))
+
+	m := logJSON(t, jsonHandler, slog.LevelInfo, err)
+	errs := m["err"].(map[string]interface{})["errors"].(map[string]interface{})
+	assert.Equal(t, float64(ErrUserNoRegister), errs["0"].(map[string]interface{})["code"])
+	assert.Equal(t, "std error", errs["1"])
+}
+
+func TestLogHandler(t *testing.T) {
+	h := func(buf *bytes.Buffer) slog.Handler {
+		return NewLogHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	}
+
+	m := logJSON(t, h, slog.LevelInfo, NewWithCode(ErrEOF, "eof"))
+	assert.Equal(t, "ERROR", m["level"])
+	assert.Equal(t, float64(ErrEOF), m["code"])
+
+	m = logJSON(t, h, slog.LevelInfo, NewWithCode(ErrUserNoRegister, "uid %d", 42))
+	assert.Equal(t, "WARN", m["level"])
+	assert.Equal(t, float64(ErrUserNoRegister), m["code"])
+
+	assert.Nil(t, logJSON(t, h, slog.LevelInfo, nil))
+}
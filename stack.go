@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // user for stack print with '%-v' format.
@@ -28,40 +31,85 @@ type Frame uintptr
 // multiple frames may have the same PC value.
 func (f Frame) pc() uintptr { return uintptr(f) - 1 }
 
-// file returns the full path to the file that contains the
-// function for this Frame's pc.
+// file returns the path to the file that contains the
+// function for this Frame's pc, rendered with the path mode.
 func (f Frame) file() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return unknown
-	}
-
-	file, _ := fn.FileLine(f.pc())
-
-	return file
+	return symbolize(uintptr(f))[0].path()
 }
 
 // line returns the line number of source code of the
 // function for this Frame's pc.
 func (f Frame) line() int {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return 0
+	return symbolize(uintptr(f))[0].line
+}
+
+// name returns the name of this function, if known.
+func (f Frame) name() string {
+	return symbolize(uintptr(f))[0].function
+}
+
+// symbol is a symbolized logical frame.
+type symbol struct {
+	function string
+	file     string
+	line     int
+}
+
+// symbols caches the logical frames of the program counters symbolized in
+// the process, so repeated formatting of an error site doesn't re-resolve them.
+var symbols sync.Map // map[uintptr][]symbol
+
+// symbolize returns the logical frames of the program counter pc as returned by
+// runtime.Callers. runtime.Callers records a pc for each inlined call already, so
+// pc resolves to the single frame of the function it is in; the loop drains the
+// frames runtime.CallersFrames yields anyway, skipping the ones without function.
+// There is always at least one frame, unknown if pc can not be symbolized.
+func symbolize(pc uintptr) []symbol {
+	if syms, ok := symbols.Load(pc); ok {
+		return syms.([]symbol)
 	}
 
-	_, line := fn.FileLine(f.pc())
+	var syms []symbol
+
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			syms = append(syms, symbol{frame.Function, frame.File, frame.Line})
+		}
 
-	return line
+		if !more {
+			break
+		}
+	}
+
+	if len(syms) == 0 {
+		syms = []symbol{{unknown, unknown, 0}}
+	}
+
+	actual, _ := symbols.LoadOrStore(pc, syms)
+
+	return actual.([]symbol)
 }
 
-// name returns the name of this function, if known.
-func (f Frame) name() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return unknown
+// symbolizeAll returns the logical frames of pcs, innermost first.
+func symbolizeAll(pcs []uintptr) []symbol {
+	syms := make([]symbol, 0, len(pcs))
+	for _, pc := range pcs {
+		syms = append(syms, symbolize(pc)...)
 	}
 
-	return fn.Name()
+	return syms
+}
+
+// Format formats the symbol as Frame does for '%+v'.
+//nolint: errcheck // WriteString could no check in pkg
+func (sym symbol) Format(s fmt.State, verb rune) {
+	io.WriteString(s, sym.function)
+	io.WriteString(s, "\n\t")
+	io.WriteString(s, sym.path())
+	io.WriteString(s, ":")
+	io.WriteString(s, strconv.Itoa(sym.line))
 }
 
 // Format formats the frame according to the fmt.Formatter interface.
@@ -110,9 +158,61 @@ func (f Frame) MarshalText() ([]byte, error) {
 	return []byte(fmt.Sprintf("%s %s:%d", name, f.file(), f.line())), nil
 }
 
+// FrameInfo is the structured information of a Frame.
+type FrameInfo struct {
+	// Function is the package path-qualified function name, e.g. github.com/go-leo/errors.New.
+	Function string
+	// Package is the import path of the package of Function, e.g. github.com/go-leo/errors.
+	Package string
+	// File is the absolute path of the source file, regardless of the path mode.
+	File string
+	// Line is the line number in File.
+	Line int
+	// PC is the program counter of the frame.
+	PC uintptr
+}
+
+// Info returns the structured information of the frame.
+// The frame of an inlined call is the one of the innermost inlined function.
+func (f Frame) Info() FrameInfo {
+	return symbolize(uintptr(f))[0].info(f.pc())
+}
+
+// info returns the FrameInfo of sym at pc.
+func (sym symbol) info(pc uintptr) FrameInfo {
+	return FrameInfo{
+		Function: sym.function,
+		Package:  packagePath(sym.function),
+		File:     sym.file,
+		Line:     sym.line,
+		PC:       pc,
+	}
+}
+
+// StackTracer is implemented by errors which carry a stack trace, like the ones
+// created by NewWithStack, WithStack and NewWithCode. It is the same contract as
+// the one of github.com/pkg/errors.
+type StackTracer interface {
+	StackTrace() StackTrace
+}
+
 // StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
 type StackTrace []Frame
 
+// Frames returns the structured information of the frames, from innermost to
+// outermost, one FrameInfo per Frame. The inlined calls have Frames of their own,
+// see runtime.Callers.
+func (st StackTrace) Frames() []FrameInfo {
+	infos := make([]FrameInfo, 0, len(st))
+	for _, f := range st {
+		for _, sym := range symbolize(uintptr(f)) {
+			infos = append(infos, sym.info(f.pc()))
+		}
+	}
+
+	return infos
+}
+
 // Format formats the stack of Frames according to the fmt.Formatter interface.
 //
 //    %s	lists source files for each Frame in the stack
@@ -158,8 +258,68 @@ func (st StackTrace) formatSlice(s fmt.State, verb rune) {
 	io.WriteString(s, "]")
 }
 
-// stack represents a stack of program counters.
-type stack []uintptr
+// stack represents a stack of program counters, innermost first. The stack of an
+// error decoded by UnmarshalJSON or FromProto has no program counters of this
+// process, it holds the decoded frames instead.
+type stack struct {
+	pcs     []uintptr
+	decoded []symbol
+}
+
+// size returns the number of frames of s.
+func (s *stack) size() int {
+	if s == nil {
+		return 0
+	}
+	if s.decoded != nil {
+		return len(s.decoded)
+	}
+
+	return len(s.pcs)
+}
+
+// frame returns the logical frames of the i-th frame of s, see symbolize.
+func (s *stack) frame(i int) []symbol {
+	if s.decoded != nil {
+		return s.decoded[i : i+1]
+	}
+
+	return symbolize(s.pcs[i])
+}
+
+// slice returns the frames of s from i to j.
+func (s *stack) slice(i, j int) *stack {
+	if s.decoded != nil {
+		return &stack{decoded: s.decoded[i:j]}
+	}
+
+	return &stack{pcs: s.pcs[i:j]}
+}
+
+// skip returns s without its n innermost frames, e.g. the ones of a constructor
+// reported by a skip depth.
+func (s *stack) skip(n int) *stack {
+	if s == nil || n <= 0 {
+		return s
+	}
+	if n > s.size() {
+		n = s.size()
+	}
+
+	return s.slice(n, s.size())
+}
+
+// symbols returns the logical frames of s, innermost first.
+func (s *stack) symbols() []symbol {
+	if s == nil {
+		return nil
+	}
+	if s.decoded != nil {
+		return s.decoded
+	}
+
+	return symbolizeAll(s.pcs)
+}
 
 func (s *stack) Format(st fmt.State, verb rune) {
 	if s == nil {
@@ -167,29 +327,193 @@ func (s *stack) Format(st fmt.State, verb rune) {
 	}
 	switch verb {
 	case 'v':
-		pcs := []uintptr(*s)
+		syms := visibleSymbols(s.symbols())
 		switch {
 		case st.Flag('-'):
-			if len(pcs) > maxStackPrintDepth {
-				pcs = pcs[:maxStackPrintDepth]
+			if len(syms) > maxStackPrintDepth {
+				syms = syms[:maxStackPrintDepth]
 			}
 		}
-		for _, pc := range pcs {
-			f := Frame(pc)
-			fmt.Fprintf(st, "\n%+v", f)
+		for _, sym := range syms {
+			fmt.Fprintf(st, "\n%v", sym)
 		}
 	}
 }
 
+// formatElided writes the frames of s which are not shared with cause, the stack
+// of a deeper error of the chain, followed by the number of shared frames elided.
+//nolint: errcheck // WriteString could no check in pkg
+func (s *stack) formatElided(st fmt.State, cause *stack) {
+	own, shared := s.split(cause)
+	own.Format(st, 'v')
+
+	if n := len(visibleSymbols(shared.symbols())); n > 0 {
+		fmt.Fprintf(st, "\n... %d frames elided", n)
+	}
+}
+
+// split splits s into its own innermost frames and the outermost frames it
+// shares with cause. The frames are the same if they are calls of the same line,
+// so a decoded stack shares frames as well.
+func (s *stack) split(cause *stack) (own, shared *stack) {
+	if s == nil {
+		return nil, nil
+	}
+
+	i, j := s.size(), cause.size()
+	for i > 0 && j > 0 && reflect.DeepEqual(s.frame(i-1), cause.frame(j-1)) {
+		i--
+		j--
+	}
+
+	return s.slice(0, i), s.slice(i, s.size())
+}
+
+// printedStack returns the stack printed last by the '%+v' format of err: the one
+// of a withStack, the outermost one of the chain of a withCode, or the one of a
+// foreign StackTracer printing its stack, e.g. of github.com/pkg/errors.
+func printedStack(err error) *stack {
+	for ; err != nil; err = nextCause(err) {
+		switch e := err.(type) {
+		case *withStack:
+			return e.stack
+		case *withCode:
+			for _, e := range list(e) {
+				if s := ownStack(e); s != nil {
+					return s
+				}
+			}
+
+			return nil
+		case *withMessage, *withFields:
+			// formatted as their cause, followed by the message
+		case fmt.Formatter:
+			return foreignStack(err)
+		default:
+			// printed as Error()
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// formatStacks writes the stacks of errs, an error chain from the outermost
+// inwards, from the innermost outwards. The frames each stack shares with the
+// one written before it are elided.
+func formatStacks(st fmt.State, errs []error) {
+	var printed *stack
+	for i := len(errs) - 1; i >= 0; i-- {
+		if s := ownStack(errs[i]); s != nil {
+			s.formatElided(st, printed)
+			printed = s
+		}
+	}
+}
+
+// ownStack returns the stack captured by err itself, if any, without the frames
+// skipped by the skip depth of a withCode.
+func ownStack(err error) *stack {
+	switch e := err.(type) {
+	case *withStack:
+		return e.stack
+	case *withCode:
+		return e.stack.skip(e.skipDepth)
+	}
+
+	return foreignStack(err)
+}
+
+// foreignStack returns the stack of an error of another package implementing the
+// StackTracer contract with a StackTrace type of its own, e.g. of github.com/pkg/errors,
+// whose frames are program counters as well.
+func foreignStack(err error) *stack {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+
+	if t := m.Type().Out(0); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := m.Call(nil)[0]
+	if frames.Len() == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+
+	return &stack{pcs: pcs}
+}
+
+// nextCause returns the direct cause of err. Cause is preferred over Unwrap,
+// since withStack unwraps to the cause of its cause.
+func nextCause(err error) error {
+	if c, ok := err.(interface{ Cause() error }); ok {
+		return c.Cause()
+	}
+
+	return Unwrap(err)
+}
+
+// MergedStackTrace returns the stack traces of err's chain merged into one:
+// the stack of the deepest error carrying one, followed by the frames each
+// outer stack doesn't share with the stack below it, from the innermost outwards.
+// It returns nil if no error of the chain has a stack. The stacks of the errors
+// decoded by UnmarshalJSON or FromProto have no Frames, see StackTrace.
+func MergedStackTrace(err error) StackTrace {
+	var stacks []*stack
+	for ; err != nil; err = nextCause(err) {
+		if s := ownStack(err); s != nil {
+			stacks = append(stacks, s)
+		}
+	}
+
+	if len(stacks) == 0 {
+		return nil
+	}
+
+	merged := stacks[len(stacks)-1].StackTrace()
+	for i := len(stacks) - 2; i >= 0; i-- {
+		own, _ := stacks[i].split(stacks[i+1])
+		merged = append(merged, own.StackTrace()...)
+	}
+
+	return merged
+}
+
+// StackTrace returns the frames of the stack which are not hidden by the frame filters.
+// A decoded stack has no Frames, since they are program counters of this process.
 func (s *stack) StackTrace() StackTrace {
-	f := make([]Frame, len(*s))
-	for i := 0; i < len(f); i++ {
-		f[i] = Frame((*s)[i])
+	if s == nil || s.decoded != nil {
+		return nil
+	}
+
+	f := make([]Frame, 0, len(s.pcs))
+	for _, pc := range s.pcs {
+		if symbolize(pc)[0].visible() {
+			f = append(f, Frame(pc))
+		}
 	}
 
 	return f
 }
 
+// caller returns the first frame from skip on which is not hidden by the frame filters.
+func (s *stack) caller(skip int) (symbol, bool) {
+	for i := skip; i < s.size(); i++ {
+		if sym := s.frame(i)[0]; sym.visible() {
+			return sym, true
+		}
+	}
+
+	return symbol{}, false
+}
+
 // func hasStack(err interface{}) bool {
 // 	if _, ok := err.(interface{ StackTrace() StackTrace }); ok {
 // 		return true
@@ -197,19 +521,85 @@ func (s *stack) StackTrace() StackTrace {
 // 	return false
 // }
 
-func callers() *stack {
-	const depth = 32
+// StackMode controls which frames are recorded when an error is created.
+type StackMode int
+
+const (
+	// StackFull records a full trace up to the depth of the policy.
+	StackFull StackMode = iota
+	// StackOff records no stack.
+	StackOff
+	// StackCaller records only the immediate caller.
+	StackCaller
+	// StackSampled records a full trace for one in SampleRate errors,
+	// and only the immediate caller for the others.
+	StackSampled
+)
+
+// defaultStackDepth is the depth of a full trace if the policy sets none.
+const defaultStackDepth = 32
 
-	var (
-		pcs       [depth]uintptr
-		skipDepth = 3
-	)
+// StackPolicy controls the stack recorded by NewWithCode, WithStack, WrapCode etc.
+type StackPolicy struct {
+	Mode StackMode
 
-	n := runtime.Callers(skipDepth, pcs[:])
+	// Depth is the maximum number of frames of a full trace, 32 if not positive.
+	Depth int
 
-	var st stack = pcs[0:n]
+	// SampleRate is N of the one in N errors which record a full trace in StackSampled mode.
+	SampleRate int
+}
+
+// stackPolicy is the global stack policy, full traces if unset.
+var stackPolicy atomic.Pointer[StackPolicy]
+
+// stackSamples counts the errors created in StackSampled mode.
+var stackSamples uint64
+
+// SetStackPolicy set the global stack policy.
+// It is safe to call while errors are being created.
+func SetStackPolicy(policy StackPolicy) {
+	stackPolicy.Store(&policy)
+}
+
+// currentStackPolicy returns the global stack policy.
+func currentStackPolicy() StackPolicy {
+	if p := stackPolicy.Load(); p != nil {
+		return *p
+	}
+
+	return StackPolicy{Mode: StackFull}
+}
 
-	return &st
+// capture records the stack of the caller skip frames up according to p.
+// In caller only mode, extra frames above the caller are recorded as well,
+// so the frame reported by a skip depth is kept.
+func (p StackPolicy) capture(skip, extra int) *stack {
+	depth := p.Depth
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
+
+	switch p.Mode {
+	case StackOff:
+		return nil
+	case StackCaller:
+		depth = 1 + extra
+	case StackSampled:
+		if p.SampleRate > 1 && atomic.AddUint64(&stackSamples, 1)%uint64(p.SampleRate) != 0 {
+			depth = 1 + extra
+		}
+	}
+
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+1, pcs)
+
+	return &stack{pcs: pcs[0:n]}
+}
+
+// callers records the stack of the caller of the error constructor with the global policy.
+func callers() *stack {
+	return currentStackPolicy().capture(3, 0)
 }
 
 // funcname removes the path prefix component of a function's name reported by func.Name().
@@ -0,0 +1,223 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newInlined is small enough to be inlined into its callers.
+func newInlined() error {
+	return NewWithStack("inlined")
+}
+
+func TestStackInlined(t *testing.T) {
+	err := newInlined()
+	trace := fmt.Sprintf("%+v", err)
+
+	inlined := strings.Index(trace, "github.com/go-leo/errors.newInlined\n")
+	caller := strings.Index(trace, "github.com/go-leo/errors.TestStackInlined\n")
+	assert.True(t, inlined > 0, trace)
+	assert.True(t, caller > inlined, trace)
+}
+
+func TestFrame(t *testing.T) {
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	f := Frame(pcs[0])
+
+	assert.Equal(t, "github.com/go-leo/errors.TestFrame", f.name())
+	assert.True(t, strings.HasSuffix(f.file(), "stack_test.go"))
+	assert.Equal(t, 29, f.line())
+	assert.Equal(t, "stack_test.go:29", fmt.Sprintf("%v", f))
+
+	_, ok := symbols.Load(pcs[0])
+	assert.True(t, ok)
+
+	assert.Equal(t, unknown, Frame(0).name())
+	assert.Equal(t, 0, Frame(0).line())
+}
+
+func TestStackPolicy(t *testing.T) {
+	defer SetStackPolicy(StackPolicy{Mode: StackFull})
+
+	SetStackPolicy(StackPolicy{Mode: StackOff})
+	err := NewWithCode(ErrEOF, "eof")
+	assert.Nil(t, err.(*withCode).stack)
+	assert.Nil(t, err.(*withCode).StackTrace())
+	assert.Equal(t, "#0 (1002) End of input, eof [End of input]", fmt.Sprintf("%-v", err))
+	assert.Equal(t, "database conn failed!", fmt.Sprintf("%+v", NewWithStack("database conn failed!")))
+
+	SetStackPolicy(StackPolicy{Mode: StackCaller})
+	err = NewWithCode(ErrEOF, "eof")
+	assert.Len(t, err.(*withCode).stack.pcs, 1)
+	assert.Equal(t, "github.com/go-leo/errors.TestStackPolicy", Frame(err.(*withCode).stack.pcs[0]).name())
+
+	// the frame reported by the skip depth is kept
+	err = NewWithCodeX(ErrEOF, "eof", WithSkipDepth(1))
+	assert.Len(t, err.(*withCode).stack.pcs, 2)
+	assert.Contains(t, fmt.Sprintf("%-v", err), "(testing.tRunner)")
+
+	SetStackPolicy(StackPolicy{Mode: StackFull, Depth: 2})
+	assert.Len(t, NewWithCode(ErrEOF, "eof").(*withCode).stack.pcs, 2)
+
+	err = NewWithCodeX(ErrEOF, "eof", WithStackPolicy(StackPolicy{Mode: StackOff}))
+	assert.Nil(t, err.(*withCode).stack)
+
+	SetStackPolicy(StackPolicy{Mode: StackSampled, SampleRate: 4})
+	full := 0
+	for i := 0; i < 8; i++ {
+		if len(NewWithCode(ErrEOF, "eof").(*withCode).stack.pcs) > 1 {
+			full++
+		}
+	}
+	assert.Equal(t, 2, full)
+}
+
+func TestMergedStackTrace(t *testing.T) {
+	assert.Nil(t, MergedStackTrace(nil))
+	assert.Nil(t, MergedStackTrace(New("no stack")))
+
+	inner := NewWithStack("inner")
+	assert.Equal(t, inner.(*withStack).StackTrace(), MergedStackTrace(inner))
+
+	err := WrapCode(inner, ErrInvalidJSON, "outer")
+	merged := MergedStackTrace(err)
+	innerTrace := inner.(*withStack).StackTrace()
+	assert.Equal(t, innerTrace, merged[:len(innerTrace)])
+	assert.Len(t, merged, len(innerTrace)+1)
+	assert.Equal(t, "github.com/go-leo/errors.TestMergedStackTrace", merged[len(merged)-1].name())
+}
+
+func TestFrameInfo(t *testing.T) {
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	info := Frame(pcs[0]).Info()
+
+	assert.Equal(t, "github.com/go-leo/errors.TestFrameInfo", info.Function)
+	assert.Equal(t, "github.com/go-leo/errors", info.Package)
+	assert.True(t, strings.HasSuffix(info.File, "/stack_test.go"), info.File)
+	assert.True(t, strings.HasPrefix(info.File, "/"), info.File)
+	assert.Equal(t, 97, info.Line)
+	assert.Equal(t, pcs[0]-1, info.PC)
+}
+
+func TestStackTracer(t *testing.T) {
+	var _ StackTracer = (*withStack)(nil)
+	var _ StackTracer = (*withCode)(nil)
+
+	var st StackTracer
+	assert.True(t, As(NewWithCode(ErrInvalidJSON, "coded"), &st))
+
+	frames := st.StackTrace().Frames()
+	assert.NotEmpty(t, frames)
+	assert.Equal(t, "github.com/go-leo/errors.TestStackTracer", frames[0].Function)
+
+	assert.True(t, As(newInlined(), &st))
+	frames = st.StackTrace().Frames()
+	assert.Equal(t, "github.com/go-leo/errors.newInlined", frames[0].Function)
+	assert.Equal(t, "github.com/go-leo/errors.TestStackTracer", frames[1].Function)
+}
+
+func TestStackPolicyConcurrent(t *testing.T) {
+	defer SetStackPolicy(StackPolicy{Mode: StackFull})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetStackPolicy(StackPolicy{Mode: StackCaller})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		assert.NotNil(t, NewWithCode(ErrEOF, "eof"))
+	}
+	<-done
+}
+
+// foreignError is an error of another package with a stack, like the ones of github.com/pkg/errors.
+type foreignError struct {
+	frames []foreignFrame
+}
+
+type foreignFrame uintptr
+
+type foreignStackTrace []foreignFrame
+
+func newForeignError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+
+	frames := make([]foreignFrame, n)
+	for i, pc := range pcs[:n] {
+		frames[i] = foreignFrame(pc)
+	}
+
+	return &foreignError{frames}
+}
+
+func (e *foreignError) Error() string { return "foreign" }
+
+func (e *foreignError) StackTrace() foreignStackTrace { return e.frames }
+
+// Format prints the stack for '%+v', as github.com/pkg/errors does.
+func (e *foreignError) Format(s fmt.State, verb rune) {
+	fmt.Fprint(s, e.Error())
+	if verb == 'v' && s.Flag('+') {
+		for _, f := range e.frames {
+			fmt.Fprintf(s, "\n%+v", Frame(f))
+		}
+	}
+}
+
+func TestStackElided(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"WrapCode over NewWithStack", WrapCode(NewWithStack("inner"), ErrInvalidJSON, "outer")},
+		{"WithStack on NewWithCode", WithStack(NewWithCode(ErrEOF, "inner"))},
+		{"WithStack on a foreign error", WithStack(newForeignError())},
+		{"WrapCode over a foreign error", WrapCode(newForeignError(), ErrInvalidJSON, "outer")},
+	}
+
+	for _, tt := range tests {
+		trace := fmt.Sprintf("%+v", tt.err)
+		assert.Equal(t, 1, strings.Count(trace, "\ntesting.tRunner\n"), tt.name+"\n"+trace)
+		assert.Regexp(t, `\n\.\.\. \d+ frames elided$`, trace, tt.name)
+	}
+
+	// the frames not shared are kept
+	err := WrapCode(NewWithStack("inner"), ErrInvalidJSON, "outer")
+	trace := fmt.Sprintf("%+v", WrapStack(err, "again"))
+	assert.Equal(t, 1, strings.Count(trace, "\ntesting.tRunner\n"), trace)
+	assert.Equal(t, 2, strings.Count(trace, "\ngithub.com/go-leo/errors.TestStackElided\n"), trace)
+}
+
+func TestMergedStackTraceForeign(t *testing.T) {
+	foreign := newForeignError()
+	err := WithStack(foreign)
+
+	merged := MergedStackTrace(err)
+	assert.Len(t, merged, len(foreign.(*foreignError).frames)+1)
+	assert.Equal(t, "github.com/go-leo/errors.newForeignError", merged[0].name())
+	assert.Equal(t, "github.com/go-leo/errors.TestMergedStackTraceForeign", merged[len(merged)-1].name())
+}
+
+func newSkippedError() error {
+	return NewWithCodeX(ErrInvalidJSON, "skipped", WithSkipDepth(1))
+}
+
+func TestStackSkipDepth(t *testing.T) {
+	err := newSkippedError()
+
+	trace := fmt.Sprintf("%+v", err)
+	assert.NotContains(t, trace, "\ngithub.com/go-leo/errors.newSkippedError\n", trace)
+	assert.Contains(t, trace, "\ngithub.com/go-leo/errors.TestStackSkipDepth\n", trace)
+
+	merged := MergedStackTrace(err)
+	assert.Equal(t, "github.com/go-leo/errors.TestStackSkipDepth", merged[0].name())
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits the bytes of an error response read by ParseHTTPResponse.
+const maxErrorBodySize = 64 << 10
+
+// ParseHTTPResponse returns the coded error described by a response of an error
+// status, 4xx or 5xx, or nil otherwise, so the redirects and 304 Not Modified
+// are not errors. The HTTPError and Problem bodies
+// written by WriteHTTP are converted back into the remote code, message and
+// reference. Other bodies are converted into an error of UnknownCoder's code
+// with the HTTP status of the response.
+// The body stays readable after ParseHTTPResponse returns.
+func ParseHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return WithStack(err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case contentTypeProblem:
+		if p, err := ParseProblem(body); err == nil {
+			return p.Err()
+		}
+	case contentTypeJSON:
+		herr := new(HTTPError)
+		if err := json.Unmarshal(body, herr); err == nil && herr.Code != 0 {
+			coder := defaultCoder{herr.Code, resp.StatusCode, herr.Message, herr.Reference}
+
+			return newRemote(nil, coder, herr.Message, herr.Metadata)
+		}
+	}
+
+	message := http.StatusText(resp.StatusCode)
+	if strings.HasPrefix(mediaType, "text/") && len(body) > 0 {
+		message = strings.TrimSpace(string(body))
+	}
+
+	return newRemote(nil, defaultCoder{UnknownCoder.Code(), resp.StatusCode, http.StatusText(resp.StatusCode), ""}, message, nil)
+}
+
+// NewRoundTripper returns an http.RoundTripper which converts the 4xx and 5xx
+// responses of next into coded errors with ParseHTTPResponse. The body of
+// such responses is closed. If next is nil, http.DefaultTransport is used.
+func NewRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &roundTripper{next: next}
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ParseHTTPResponse(resp); err != nil {
+		_ = resp.Body.Close()
+
+		return nil, err
+	}
+
+	return resp, nil
+}
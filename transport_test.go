@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHTTPResponse(t *testing.T) {
+	coded := WithFields(NewWithCode(ErrUserNoRegister, "uid %d", 42), "uid", 42)
+
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		wantCode    int
+		wantStatus  int
+		wantMessage string
+		wantFields  map[string]interface{}
+	}{
+		{
+			name:        "json",
+			handler:     func(w http.ResponseWriter, r *http.Request) { WriteHTTP(w, r, coded) },
+			wantCode:    ErrUserNoRegister,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "user no register, go to create",
+			wantFields:  map[string]interface{}{"uid": "42"},
+		},
+		{
+			name: "problem",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				r.Header.Set("Accept", "application/problem+json")
+				WriteHTTP(w, r, coded)
+			},
+			wantCode:    ErrUserNoRegister,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "user no register, go to create",
+			wantFields:  map[string]interface{}{"uid": "42"},
+		},
+		{
+			name: "foreign json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"error":"no such route"}`)
+			},
+			wantCode:    UnknownCoder.Code(),
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Not Found",
+			wantFields:  map[string]interface{}{},
+		},
+		{
+			name:        "text",
+			handler:     func(w http.ResponseWriter, r *http.Request) { http.Error(w, "upstream down", http.StatusBadGateway) },
+			wantCode:    UnknownCoder.Code(),
+			wantStatus:  http.StatusBadGateway,
+			wantMessage: "upstream down",
+			wantFields:  map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(http.MethodGet, "/user", nil))
+			resp := w.Result()
+			body := w.Body.String()
+
+			err := ParseHTTPResponse(resp)
+			coder := ParseCoder(err)
+			assert.True(t, IsRemote(err))
+			assert.Equal(t, tt.wantCode, coder.Code())
+			assert.Equal(t, tt.wantStatus, coder.HTTPStatus())
+			assert.Equal(t, tt.wantMessage, internalMessage(err))
+			assert.Equal(t, tt.wantFields, Fields(err))
+
+			got, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, body, string(got))
+		})
+	}
+	// the successes, redirects and 304 are not errors
+	for _, status := range []int{http.StatusOK, http.StatusFound, http.StatusNotModified} {
+		resp := &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}
+		assert.NoError(t, ParseHTTPResponse(resp))
+	}
+}
+
+func TestRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(HTTPMiddleware(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = io.WriteString(w, "ok")
+			return nil
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+			return nil
+		case "/cached":
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+
+		return NewWithCode(ErrUserNoRegister, "uid %d", 42)
+	})))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRoundTripper(nil)}
+
+	resp, err := client.Get(srv.URL + "/ok")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+
+	// the redirects are followed
+	resp, err = client.Get(srv.URL + "/moved")
+	assert.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+
+	resp, err = client.Get(srv.URL + "/cached")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	_, err = client.Get(srv.URL + "/user")
+	assert.True(t, IsCode(err, ErrUserNoRegister))
+	assert.True(t, strings.HasSuffix(err.Error(), "user no register, go to create"))
+}